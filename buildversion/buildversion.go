@@ -0,0 +1,262 @@
+/*
+Package buildversion resolves and exposes the version of the running program,
+so that services do not each need their own -ldflags plumbing.
+
+The version is taken from the first of these which is available:
+
+ 1. The Version variable, injected at link time using:
+    go build -ldflags "-X github.com/samsalisbury/semv/buildversion.Version=v1.2.3"
+ 2. The version of the main module, as recorded by the go command when it
+    builds a module at a tagged version or pseudo-version, e.g. by
+    "go install example.com/cmd@v1.2.3".
+ 3. The VCS revision the program was built from, as the version 0.0.0 with
+    the revision, commit time and dirty flag in its build metadata, e.g.
+    "0.0.0+sha.abc123def456.date.20240102150405.dirty". See
+    semv.Version.WithBuildInfo.
+
+Programs can serve the version over HTTP using Handler, and print it using a
+-version flag added by RegisterFlag, after which they should exit.
+*/
+package buildversion
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samsalisbury/semv"
+)
+
+// Version is the version of the program, injected at link time, e.g. using
+// -ldflags "-X github.com/samsalisbury/semv/buildversion.Version=v1.2.3". A
+// leading "v" is ignored. If it is empty or not a valid version, Get falls
+// back to the build information recorded by the go command.
+var Version string
+
+type (
+	// Source describes where the version of an Info came from.
+	Source string
+	// Info is the version of the running program, along with the VCS
+	// information recorded when it was built.
+	Info struct {
+		Version semv.Version `json:"version"`
+		Source  Source       `json:"source"`
+		// Revision is the full VCS revision the program was built from, if
+		// known.
+		Revision string `json:"revision,omitempty"`
+		// Time is the commit time of Revision, if known.
+		Time *time.Time `json:"time,omitempty"`
+		// Dirty is true if the working tree had uncommitted changes.
+		Dirty bool `json:"dirty,omitempty"`
+		// GoVersion is the version of Go the program was built with.
+		GoVersion string `json:"goVersion,omitempty"`
+	}
+	// PseudoVersion is a Go module pseudo-version, which identifies an
+	// untagged revision, e.g. "v1.2.4-0.20240102150405-abcdef123456".
+	PseudoVersion struct {
+		// Base is the tagged version the pseudo-version was derived from, e.g.
+		// 1.2.3 for "v1.2.4-0.20240102150405-abcdef123456", or nil if there
+		// was no earlier tag.
+		Base *semv.Version
+		// Time is the commit time of Revision.
+		Time time.Time
+		// Revision is the 12 character revision prefix.
+		Revision string
+	}
+	// NotPseudoVersion is an error returned by ParsePseudoVersion for
+	// versions which are not pseudo-versions.
+	NotPseudoVersion struct {
+		Version string
+	}
+)
+
+const (
+	// FromLdflags means the version was injected into the Version variable.
+	FromLdflags Source = "ldflags"
+	// FromModule means the version is the main module version.
+	FromModule Source = "module"
+	// FromVCS means the version was derived from the VCS revision.
+	FromVCS Source = "vcs"
+	// Unknown means no version information was available, and the version
+	// is 0.0.0.
+	Unknown Source = "unknown"
+)
+
+// pseudoTimeLayout is the time layout used in pseudo-versions.
+const pseudoTimeLayout = "20060102150405"
+
+func (err NotPseudoVersion) Error() string {
+	return fmt.Sprintf("%q is not a pseudo-version", err.Version)
+}
+
+// get resolves the version of the running program, once.
+var get = sync.OnceValue(func() Info {
+	info, _ := debug.ReadBuildInfo()
+	return resolve(Version, info)
+})
+
+// Get returns the version of the running program, as described in the
+// package documentation.
+func Get() Info {
+	return get()
+}
+
+// resolve resolves the version from the injected version and the build
+// information, which may be nil.
+func resolve(injected string, bi *debug.BuildInfo) Info {
+	info := Info{Source: Unknown, Version: semv.NewMajorMinorPatch(0, 0, 0)}
+	if bi != nil {
+		info.GoVersion = bi.GoVersion
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Revision = s.Value
+			case "vcs.time":
+				if t, err := time.Parse(time.RFC3339, s.Value); err == nil {
+					info.Time = &t
+				}
+			case "vcs.modified":
+				info.Dirty = s.Value == "true"
+			}
+		}
+	}
+	if v, err := parse(injected); injected != "" && err == nil {
+		info.Version, info.Source = v, FromLdflags
+		return info
+	}
+	if bi == nil {
+		return info
+	}
+	if v, err := parse(bi.Main.Version); bi.Main.Version != "" && bi.Main.Version != "(devel)" && err == nil {
+		info.Version, info.Source = v, FromModule
+		if p, err := ParsePseudoVersion(bi.Main.Version); err == nil && info.Revision == "" {
+			info.Revision, info.Time = p.Revision, &p.Time
+		}
+		info.Dirty = info.Dirty || v.BuildMetadata().Dirty()
+		return info
+	}
+	if info.Revision != "" {
+		info.Version, info.Source = info.Version.WithBuildSettings(bi.Settings), FromVCS
+	}
+	return info
+}
+
+func parse(s string) (semv.Version, error) {
+	return semv.Parse(strings.TrimPrefix(s, "v"))
+}
+
+// String returns the version, followed by the revision and whether it was
+// dirty if they are known and not already part of the version, e.g.
+// "1.2.3 (revision abc123def456, dirty)".
+func (i Info) String() string {
+	s := i.Version.String()
+	if i.Revision == "" || i.Source == FromVCS {
+		return s
+	}
+	rev := i.Revision
+	if len(rev) > 12 {
+		rev = rev[:12]
+	}
+	s += " (revision " + rev
+	if i.Dirty {
+		s += ", dirty"
+	}
+	return s + ")"
+}
+
+// ServeHTTP writes the info as a JSON object, e.g.
+//
+//	{"version":"1.2.3","source":"ldflags","revision":"...","goVersion":"go1.22.0"}
+func (i Info) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == http.MethodHead {
+		return
+	}
+	json.NewEncoder(w).Encode(i)
+}
+
+// Handler returns an HTTP handler which serves the version of the running
+// program as JSON, e.g. at "/version".
+func Handler() http.Handler {
+	return Get()
+}
+
+// RegisterFlag adds a boolean -version flag to fs, which may also be given as
+// --version. When the flag is parsed, the version of the running program is
+// printed to w, and the returned bool is set to true, so that the caller can
+// exit after parsing its flags:
+//
+//	shown := buildversion.RegisterFlag(flag.CommandLine, os.Stdout)
+//	flag.Parse()
+//	if *shown {
+//		os.Exit(0)
+//	}
+func RegisterFlag(fs *flag.FlagSet, w io.Writer) *bool {
+	shown := new(bool)
+	fs.BoolFunc("version", "print the version and exit", func(s string) error {
+		if show, err := strconv.ParseBool(s); err != nil || !show || *shown {
+			return err
+		}
+		fmt.Fprintln(w, Get())
+		*shown = true
+		return nil
+	})
+	return shown
+}
+
+// ParsePseudoVersion parses a Go module pseudo-version, in any of its three
+// forms:
+//
+//	vX.0.0-yyyymmddhhmmss-abcdefabcdef, when there is no earlier tag
+//	vX.Y.(Z+1)-0.yyyymmddhhmmss-abcdefabcdef, after the tag vX.Y.Z
+//	vX.Y.Z-pre.0.yyyymmddhhmmss-abcdefabcdef, after the tag vX.Y.Z-pre
+//
+// Metadata such as "+incompatible" or "+dirty" is permitted. It returns a
+// NotPseudoVersion error if s is a version, but not a pseudo-version.
+func ParsePseudoVersion(s string) (PseudoVersion, error) {
+	v, err := parse(s)
+	if err != nil {
+		return PseudoVersion{}, err
+	}
+	ids := strings.Split(v.Pre, ".")
+	timestamp, rev, ok := strings.Cut(ids[len(ids)-1], "-")
+	if !ok || len(timestamp) != len(pseudoTimeLayout) || len(rev) != 12 || strings.Trim(rev, "0123456789abcdef") != "" {
+		return PseudoVersion{}, NotPseudoVersion{s}
+	}
+	t, err := time.Parse(pseudoTimeLayout, timestamp)
+	if err != nil {
+		return PseudoVersion{}, NotPseudoVersion{s}
+	}
+	p := PseudoVersion{Time: t, Revision: rev}
+	switch n := len(ids); {
+	case n == 1 && v.Minor == 0 && v.Patch == 0:
+	case n == 2 && ids[0] == "0" && v.Patch > 0:
+		// The patch may be too large for an int, so it is decremented
+		// exactly.
+		major, minor, patch := v.Big()
+		base, err := semv.Parse(fmt.Sprintf("%s.%s.%s", major, minor, patch.Sub(patch, big.NewInt(1))))
+		if err != nil {
+			return PseudoVersion{}, err
+		}
+		p.Base = &base
+	case n > 2 && ids[n-2] == "0":
+		base := v.MajorMinorPatch().SetPre(strings.Join(ids[:n-2], "."))
+		p.Base = &base
+	default:
+		return PseudoVersion{}, NotPseudoVersion{s}
+	}
+	return p, nil
+}
@@ -0,0 +1,168 @@
+package buildversion
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"testing"
+	"time"
+)
+
+var vcsSettings = []debug.BuildSetting{
+	{Key: "vcs", Value: "git"},
+	{Key: "vcs.revision", Value: "0123456789abcdef0123456789abcdef01234567"},
+	{Key: "vcs.time", Value: "2024-01-02T15:04:05Z"},
+	{Key: "vcs.modified", Value: "true"},
+}
+
+func TestResolve(t *testing.T) {
+	withModule := func(version string, settings []debug.BuildSetting) *debug.BuildInfo {
+		return &debug.BuildInfo{GoVersion: "go1.22.0", Main: debug.Module{Path: "example.com/svc", Version: version}, Settings: settings}
+	}
+	tests := []struct {
+		name     string
+		injected string
+		bi       *debug.BuildInfo
+		version  string
+		source   Source
+		revision string
+		dirty    bool
+	}{
+		{"injected", "v1.2.3", withModule("v1.0.0", vcsSettings), "1.2.3", FromLdflags, "0123456789abcdef0123456789abcdef01234567", true},
+		{"injected without build info", "1.2.3-rc.1", nil, "1.2.3-rc.1", FromLdflags, "", false},
+		{"invalid injected", "dev", withModule("v1.0.0", nil), "1.0.0", FromModule, "", false},
+		{"module", "", withModule("v1.0.0", nil), "1.0.0", FromModule, "", false},
+		{"pseudo-version", "", withModule("v1.2.4-0.20240102150405-abcdef123456+dirty", nil),
+			"1.2.4-0.20240102150405-abcdef123456+dirty", FromModule, "abcdef123456", true},
+		{"devel", "", withModule("(devel)", vcsSettings), "0.0.0+sha.0123456789ab.date.20240102150405.dirty", FromVCS,
+			"0123456789abcdef0123456789abcdef01234567", true},
+		{"devel without vcs", "", withModule("(devel)", nil), "0.0.0", Unknown, "", false},
+		{"no build info", "", nil, "0.0.0", Unknown, "", false},
+	}
+	for _, test := range tests {
+		info := resolve(test.injected, test.bi)
+		if info.Version.String() != test.version || info.Source != test.source || info.Revision != test.revision || info.Dirty != test.dirty {
+			t.Errorf("%s: got %s, %s, %q, %t; want %s, %s, %q, %t", test.name,
+				info.Version, info.Source, info.Revision, info.Dirty,
+				test.version, test.source, test.revision, test.dirty)
+		}
+	}
+}
+
+func TestResolve_Time(t *testing.T) {
+	info := resolve("", &debug.BuildInfo{Main: debug.Module{Version: "v0.0.0-20240102150405-abcdef123456"}})
+	if expected := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC); info.Time == nil || !info.Time.Equal(expected) {
+		t.Errorf("got Time == %v; want %s", info.Time, expected)
+	}
+}
+
+func TestParsePseudoVersion(t *testing.T) {
+	tests := map[string]string{
+		"v0.0.0-20240102150405-abcdef123456":                "<nil>",
+		"v2.0.0-20240102150405-abcdef123456":                "<nil>",
+		"v1.2.4-0.20240102150405-abcdef123456":              "1.2.3",
+		"v1.2.4-0.20240102150405-abcdef123456+incompatible": "1.2.3",
+		"v1.2.3-rc.1.0.20240102150405-abcdef123456":         "1.2.3-rc.1",
+	}
+	for input, base := range tests {
+		p, err := ParsePseudoVersion(input)
+		if err != nil {
+			t.Errorf("ParsePseudoVersion(%s): %s", input, err)
+			continue
+		}
+		actual := "<nil>"
+		if p.Base != nil {
+			actual = p.Base.String()
+		}
+		if actual != base || p.Revision != "abcdef123456" || !p.Time.Equal(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)) {
+			t.Errorf("ParsePseudoVersion(%s): got %s, %s, %s; want %s, abcdef123456, 2024-01-02 15:04:05", input, actual, p.Revision, p.Time, base)
+		}
+	}
+}
+
+func TestParsePseudoVersion_BigComponents(t *testing.T) {
+	tests := map[string]string{
+		"v1.0.20261016123045999001-0.20240102150405-abcdef123456":      "1.0.20261016123045999000",
+		"v1.0.10000000000000000000-0.20240102150405-abcdef123456":      "1.0.9999999999999999999",
+		"v20261016123045999000.1.4-0.20240102150405-abcdef123456":      "20261016123045999000.1.3",
+		"v1.0.20261016123045999000-rc.1.0.20240102150405-abcdef123456": "1.0.20261016123045999000-rc.1",
+	}
+	for input, base := range tests {
+		p, err := ParsePseudoVersion(input)
+		if err != nil {
+			t.Errorf("ParsePseudoVersion(%s): %s", input, err)
+			continue
+		}
+		if p.Base == nil || p.Base.String() != base {
+			t.Errorf("ParsePseudoVersion(%s): got base %v; want %s", input, p.Base, base)
+		}
+	}
+}
+
+func TestParsePseudoVersion_Invalid(t *testing.T) {
+	for _, input := range []string{
+		"v1.2.3",
+		"v1.2.3-rc.1",
+		"v1.2.0-20240102150405-abcdef123456",
+		"v1.2.0-0.20240102150405-abcdef123456",
+		"v1.2.4-0.20240102150405-abcdef12345",
+		"v1.2.4-0.20241302150405-abcdef123456",
+		"v1.2.4-1.20240102150405-abcdef123456",
+	} {
+		var notPseudo NotPseudoVersion
+		if _, err := ParsePseudoVersion(input); !errors.As(err, &notPseudo) {
+			t.Errorf("ParsePseudoVersion(%s): got %v; want a NotPseudoVersion error", input, err)
+		}
+	}
+	if _, err := ParsePseudoVersion("not a version"); err == nil {
+		t.Errorf("got nil error parsing an invalid version")
+	}
+}
+
+func TestInfo_String(t *testing.T) {
+	tests := map[string]Info{
+		"1.2.3":                                resolve("1.2.3", nil),
+		"1.2.3 (revision 0123456789ab, dirty)": resolve("1.2.3", &debug.BuildInfo{Settings: vcsSettings}),
+		"0.0.0+sha.0123456789ab.date.20240102150405.dirty": resolve("", &debug.BuildInfo{Settings: vcsSettings}),
+	}
+	for expected, info := range tests {
+		if actual := info.String(); actual != expected {
+			t.Errorf("got %q; want %q", actual, expected)
+		}
+	}
+}
+
+func TestInfo_ServeHTTP(t *testing.T) {
+	info := resolve("1.2.3", &debug.BuildInfo{GoVersion: "go1.22.0", Settings: vcsSettings[:2]})
+	rec := httptest.NewRecorder()
+	info.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	expected := `{"version":"1.2.3","source":"ldflags","revision":"0123456789abcdef0123456789abcdef01234567","goVersion":"go1.22.0"}` + "\n"
+	if rec.Code != http.StatusOK || rec.Body.String() != expected || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("got %d %q (%s); want 200 %q (application/json)", rec.Code, rec.Body, rec.Header().Get("Content-Type"), expected)
+	}
+	rec = httptest.NewRecorder()
+	info.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/version", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: got %d; want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestRegisterFlag(t *testing.T) {
+	for _, args := range [][]string{{"-version"}, {"--version"}, {"-version", "-version"}, {"-version=false"}, {}} {
+		var out bytes.Buffer
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		shown := RegisterFlag(fs, &out)
+		if err := fs.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		expectShown := len(args) != 0 && args[0] != "-version=false"
+		if expected := Get().String() + "\n"; expectShown && (!*shown || out.String() != expected) {
+			t.Errorf("%q: got shown %t, output %q; want true, %q", args, *shown, out.String(), expected)
+		} else if !expectShown && (*shown || out.Len() != 0) {
+			t.Errorf("%q: got shown %t, output %q; want false and no output", args, *shown, out.String())
+		}
+	}
+}
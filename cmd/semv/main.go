@@ -0,0 +1,138 @@
+/*
+Command semv works with semantic versions from the command line.
+
+Usage:
+
+	semv -version
+	semv validate-next [-tags file | -git dir] [-prefix v] version
+
+-version prints the version of semv itself.
+
+validate-next checks that version is a valid next release, given the existing
+releases, as described by semv.ValidateNext. The existing releases are read
+from a tags file, with one tag per line, or from the tags of a local git
+repository, which is the current directory by default. Tags which are not
+versions are ignored. It exits with status 1 if the version is not valid, and
+prints the reasons.
+*/
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/samsalisbury/semv"
+	"github.com/samsalisbury/semv/buildversion"
+)
+
+// commands are the subcommands, which return the exit status.
+var commands = map[string]func(args []string, stdout, stderr io.Writer) int{
+	"validate-next": validateNext,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("semv", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	versionShown := buildversion.RegisterFlag(flags, stdout)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *versionShown {
+		return 0
+	}
+	args = flags.Args()
+	if len(args) == 0 || commands[args[0]] == nil {
+		fmt.Fprintln(stderr, "usage: semv -version | validate-next [-tags file | -git dir] [-prefix v] version")
+		return 2
+	}
+	return commands[args[0]](args[1:], stdout, stderr)
+}
+
+func validateNext(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate-next", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	tagsFile := flags.String("tags", "", "read existing release tags from `file`, one per line")
+	gitDir := flags.String("git", ".", "read existing release tags from the git repository in `dir`")
+	prefix := flags.String("prefix", "v", "strip `prefix` from tags and the proposed version")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "validate-next: expected exactly one proposed version")
+		return 2
+	}
+	proposed, err := semv.Parse(strings.TrimPrefix(flags.Arg(0), *prefix))
+	if err != nil {
+		fmt.Fprintf(stderr, "validate-next: %s\n", err)
+		return 2
+	}
+	var tags []string
+	if *tagsFile != "" {
+		tags, err = readTagsFile(*tagsFile)
+	} else {
+		tags, err = readGitTags(*gitDir)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "validate-next: %s\n", err)
+		return 2
+	}
+	violations := semv.ValidateNext(parseTags(tags, *prefix), proposed)
+	for _, v := range violations {
+		fmt.Fprintln(stdout, v)
+	}
+	if len(violations) != 0 {
+		return 1
+	}
+	return 0
+}
+
+// readTagsFile reads the tags in the file at path, one per line, ignoring
+// blank lines and lines beginning with #.
+func readTagsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var tags []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			tags = append(tags, line)
+		}
+	}
+	return tags, s.Err()
+}
+
+// readGitTags lists the tags of the git repository in dir.
+func readGitTags(dir string) ([]string, error) {
+	out, err := exec.Command("git", "-C", dir, "tag", "--list").Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) != 0 {
+			return nil, fmt.Errorf("listing git tags: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("listing git tags: %s", err)
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// parseTags returns the versions named by tags, with prefix removed,
+// ignoring tags which are not versions.
+func parseTags(tags []string, prefix string) semv.VersionList {
+	var vl semv.VersionList
+	for _, tag := range tags {
+		if v, err := semv.Parse(strings.TrimPrefix(tag, prefix)); err == nil {
+			vl = append(vl, v)
+		}
+	}
+	return vl
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func runCommand(args ...string) (status int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	status = run(args, &out, &errOut)
+	return status, out.String(), errOut.String()
+}
+
+func TestValidateNext_TagsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tags")
+	tags := "# releases\nv1.0.0\nv1.1.0\n\nlatest\nv1.2.0-rc.1\n"
+	if err := os.WriteFile(path, []byte(tags), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		proposed string
+		status   int
+		stdout   string
+	}{
+		{"v1.2.0", 0, ""},
+		{"1.1.1", 0, ""},
+		{"v1.4.0", 1, "1.4.0 skips versions after 1.2.0-rc.1; want one of 1.2.0, 1.2.1, 1.3.0 or 2.0.0\n"},
+		{"v1.1.0+build.2", 1, "1.1.0+build.2 has the same precedence as existing release 1.1.0\n"},
+	}
+	for _, test := range tests {
+		status, stdout, stderr := runCommand("validate-next", "-tags", path, test.proposed)
+		if status != test.status || stdout != test.stdout {
+			t.Errorf("validate-next %s: got status %d, output %q (stderr %q); want status %d, output %q",
+				test.proposed, status, stdout, stderr, test.status, test.stdout)
+		}
+	}
+}
+
+func TestValidateNext_Git(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+		{"tag", "v0.1.0"},
+		{"tag", "v0.2.0"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s: %s", args, err, out)
+		}
+	}
+	if status, stdout, stderr := runCommand("validate-next", "-git", dir, "v0.2.1"); status != 0 {
+		t.Errorf("got status %d, output %q, %q; want 0", status, stdout, stderr)
+	}
+	if status, _, _ := runCommand("validate-next", "-git", dir, "v0.3.5"); status != 1 {
+		t.Errorf("got status %d; want 1", status)
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"unknown"},
+		{"-unknown-flag"},
+		{"validate-next"},
+		{"validate-next", "-tags", "/does/not/exist", "1.0.0"},
+		{"validate-next", "-git", "/does/not/exist", "1.0.0"},
+		{"validate-next", "not-a-version"},
+	} {
+		if status, _, _ := runCommand(args...); status != 2 {
+			t.Errorf("%q: got status %d; want 2", args, status)
+		}
+	}
+}
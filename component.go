@@ -0,0 +1,147 @@
+package semv
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Semver places no limit on the size of the major, minor and patch
+// components, and some projects use long timestamps as patch numbers, e.g.
+// "1.0.20261016123045999000", which do not fit in an int. Such a component is
+// stored as math.MaxInt in its int field, with its exact decimal digits held
+// in the corresponding unexported big field of Version. The big field is only
+// consulted when the int field is math.MaxInt, so setting the int field
+// directly always takes effect. Components which fit in an int never use the
+// big field, so working with them never allocates.
+
+// isBig returns true if a component with int value n and big digits b is
+// too large for an int.
+func isBig(n int, b string) bool {
+	return n == math.MaxInt && b != ""
+}
+
+// parseComponent parses the decimal digits s as a component, returning
+// math.MaxInt and the digits without leading zeros if it is too large for an
+// int.
+func parseComponent[T string | []byte](s T) (int, string) {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		d := int(s[i] - '0')
+		if n > (math.MaxInt-d)/10 {
+			for len(s) > 1 && s[0] == '0' {
+				s = s[1:]
+			}
+			return math.MaxInt, string(s)
+		}
+		n = n*10 + d
+	}
+	return n, ""
+}
+
+// appendComponent appends the decimal digits of a component to dst.
+func appendComponent(dst []byte, n int, b string) []byte {
+	if isBig(n, b) {
+		return append(dst, b...)
+	}
+	return strconv.AppendInt(dst, int64(n), 10)
+}
+
+// componentString returns the decimal digits of a component.
+func componentString(n int, b string) string {
+	if isBig(n, b) {
+		return b
+	}
+	return strconv.Itoa(n)
+}
+
+// compareComponents returns -1, 0 or 1 if component a is less than, equal
+// to, or greater than component b.
+func compareComponents(a int, aBig string, b int, bBig string) int {
+	aIsBig, bIsBig := isBig(a, aBig), isBig(b, bBig)
+	switch {
+	case aIsBig && bIsBig:
+		if len(aBig) != len(bBig) {
+			return compareInts(len(aBig), len(bBig))
+		}
+		return strings.Compare(aBig, bBig)
+	case aIsBig:
+		return 1
+	case bIsBig:
+		return -1
+	}
+	return compareInts(a, b)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// incrementComponent returns a component one greater than the one passed in,
+// moving to the big representation when it would overflow an int.
+func incrementComponent(n int, b string) (int, string) {
+	if n != math.MaxInt {
+		return n + 1, ""
+	}
+	if !isBig(n, b) {
+		b = strconv.Itoa(n)
+	}
+	digits := []byte(b)
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] != '9' {
+			digits[i]++
+			return n, string(digits)
+		}
+		digits[i] = '0'
+	}
+	return n, "1" + string(digits)
+}
+
+// bigComponent returns a component as a big.Int.
+func bigComponent(n int, b string) *big.Int {
+	if !isBig(n, b) {
+		return big.NewInt(int64(n))
+	}
+	i, _ := new(big.Int).SetString(b, 10)
+	return i
+}
+
+// Big returns the major, minor and patch components of this version as
+// big.Ints. Unlike the Major, Minor and Patch fields, which are math.MaxInt
+// when the component is too large for an int, these are always exact.
+func (v Version) Big() (major, minor, patch *big.Int) {
+	return bigComponent(v.Major, v.bigMajor),
+		bigComponent(v.Minor, v.bigMinor),
+		bigComponent(v.Patch, v.bigPatch)
+}
+
+// MajorString returns the decimal digits of the major component, which are
+// exact however large it is.
+func (v Version) MajorString() string { return componentString(v.Major, v.bigMajor) }
+
+// MinorString returns the decimal digits of the minor component. See
+// MajorString.
+func (v Version) MinorString() string { return componentString(v.Minor, v.bigMinor) }
+
+// PatchString returns the decimal digits of the patch component. See
+// MajorString.
+func (v Version) PatchString() string { return componentString(v.Patch, v.bigPatch) }
+
+// compareMMP compares the major, minor and patch components of the versions,
+// returning -1, 0 or 1.
+func (v Version) compareMMP(other Version) int {
+	if c := compareComponents(v.Major, v.bigMajor, other.Major, other.bigMajor); c != 0 {
+		return c
+	}
+	if c := compareComponents(v.Minor, v.bigMinor, other.Minor, other.bigMinor); c != 0 {
+		return c
+	}
+	return compareComponents(v.Patch, v.bigPatch, other.Patch, other.bigPatch)
+}
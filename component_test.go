@@ -0,0 +1,155 @@
+package semv
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"testing"
+)
+
+// maxIntPlusOne is the decimal representation of math.MaxInt + 1.
+var maxIntPlusOne = func() string {
+	s := []byte(strconv.Itoa(math.MaxInt))
+	s[len(s)-1]++
+	return string(s)
+}()
+
+func TestParse_BigComponents(t *testing.T) {
+	inputs := []string{
+		"1.0.20261016123045999",
+		"1.0.20261016123045999000-rc.1+build.5",
+		"123456789012345678901234567890.0.0",
+		"1.99999999999999999999999.3",
+		"1.0." + maxIntPlusOne,
+	}
+	for _, input := range inputs {
+		v, err := Parse(input)
+		if err != nil {
+			t.Errorf("parsing %q: %s", input, err)
+			continue
+		}
+		if s := v.String(); s != input {
+			t.Errorf("got %q.String() == %q", input, s)
+		}
+		major, minor, patch := v.Big()
+		if s := major.String() + "." + minor.String() + "." + patch.String(); s != v.Format(MajorMinorPatch) {
+			t.Errorf("got Big() == %s; want %s", s, v.Format(MajorMinorPatch))
+		}
+	}
+	v := MustParse("1.0.0020261016123045999000")
+	if s := v.PatchString(); s != "20261016123045999000" {
+		t.Errorf("got PatchString() == %q; want leading zeros removed", s)
+	}
+	if v := MustParse("1.0." + strconv.Itoa(math.MaxInt)); v.PatchString() != strconv.Itoa(math.MaxInt) {
+		t.Errorf("got PatchString() == %q for math.MaxInt", v.PatchString())
+	}
+}
+
+func TestLess_BigComponents(t *testing.T) {
+	ordered := MustParseList(
+		"1.0.0",
+		"1.0.20261016123045999",
+		"1.0."+strconv.Itoa(math.MaxInt),
+		"1.0."+maxIntPlusOne,
+		"1.0.20261016123045999000-rc.1",
+		"1.0.20261016123045999000",
+		"1.0.20261016123046000000",
+		"1.0.100000000000000000000",
+		"1.1.0",
+		"99999999999999999999.0.0",
+		"100000000000000000000.0.0",
+	)
+	for i := range ordered {
+		for j := range ordered {
+			if actual, expected := ordered[i].Less(ordered[j]), i < j; actual != expected {
+				t.Errorf("got %s.Less(%s) == %t; want %t", ordered[i], ordered[j], actual, expected)
+			}
+		}
+	}
+	if !MustParse("1.0.20261016123045999000").Equals(MustParse("1.0.020261016123045999000")) {
+		t.Errorf("versions differing only in leading zeros should be equal")
+	}
+}
+
+func TestIncrement_BigComponents(t *testing.T) {
+	tests := []struct {
+		input    string
+		f        func(Version) Version
+		expected string
+	}{
+		{"1.0." + strconv.Itoa(math.MaxInt), Version.IncrementPatch, "1.0." + maxIntPlusOne},
+		{"1.0.99999999999999999999", Version.IncrementPatch, "1.0.100000000000000000000"},
+		{"1.99999999999999999999.5", Version.IncrementMinor, "1.100000000000000000000.0"},
+		{"99999999999999999999.5.5", Version.IncrementMajor, "100000000000000000000.0.0"},
+		{"1.99999999999999999999.5", Version.IncrementMajor, "2.0.0"},
+	}
+	for _, test := range tests {
+		if actual := test.f(MustParse(test.input)).String(); actual != test.expected {
+			t.Errorf("incrementing %s: got %s; want %s", test.input, actual, test.expected)
+		}
+	}
+}
+
+func TestBigComponents_Fields(t *testing.T) {
+	v := MustParse("1.0.20261016123045999000")
+	if v.Patch != math.MaxInt {
+		t.Errorf("got Patch == %d; want math.MaxInt", v.Patch)
+	}
+	v.Patch = 7
+	if s := v.String(); s != "1.0.7" {
+		t.Errorf("got %q after setting Patch; want %q", s, "1.0.7")
+	}
+}
+
+func TestBigComponents_JSON(t *testing.T) {
+	expected := MustParse("1.0.20261016123045999000-rc.1")
+	b, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual Version
+	if err := json.Unmarshal(b, &actual); err != nil {
+		t.Fatal(err)
+	}
+	if actual != expected {
+		t.Errorf("got %s after JSON round trip; want %s", actual.dump(), expected.dump())
+	}
+}
+
+func TestBigComponents_Format(t *testing.T) {
+	v := MustParse("1.0.20261016123045999000")
+	tests := map[string]string{
+		"M.m.p":        "1.0.20261016123045999000",
+		"%M.%m{.%p}":   "1.0.20261016123045999000",
+		"%025p":        "0000020261016123045999000",
+		"%M{.%m}":      "1",
+		"{build %p}\\": "%!(invalid format \"{build %p}\\\\\" at position 10: trailing backslash)",
+	}
+	for format, expected := range tests {
+		if actual := v.Format(format); actual != expected {
+			t.Errorf("got Format(%q) == %q; want %q", format, actual, expected)
+		}
+	}
+}
+
+func TestBigComponents_Grouping(t *testing.T) {
+	vl := MustParseList("99999999999999999999.1.0", "99999999999999999998.1.0", "99999999999999999999.2.0")
+	assertVersionList(t, "LatestPerMajor", vl.LatestPerMajor(), "99999999999999999998.1.0", "99999999999999999999.2.0")
+
+	var sm StatusMap
+	sm.Yank(MustParse("1.0.99999999999999999999"))
+	if sm.Get(MustParse("1.0.99999999999999999998")).Yanked {
+		t.Errorf("yanking one big version yanked another")
+	}
+}
+
+func TestLess_SmallComponentsDoNotAllocate(t *testing.T) {
+	a, b := MustParse("1.2.3-beta.2"), MustParse("1.2.3")
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = a.Less(b)
+		_ = a.IncrementPatch()
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocations; want 0", allocs)
+	}
+}
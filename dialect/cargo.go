@@ -0,0 +1,101 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samsalisbury/semv"
+)
+
+type cargo struct{}
+
+// ParseRange parses a Cargo version requirement, which is a comma-separated
+// list of comparators, all of which must be satisfied. A comparator with no
+// operator, e.g. "1.2", is treated as a caret requirement.
+func (cargo) ParseRange(s string) (semv.RangeSet, error) {
+	r := semv.Range{}
+	for _, c := range strings.Split(s, ",") {
+		cr, err := parseCargoComparator(strings.TrimSpace(c))
+		if err != nil {
+			return nil, fmt.Errorf("cargo: parsing %q: %s", s, err)
+		}
+		r = r.Intersect(cr)
+	}
+	return semv.RangeSet{r}, nil
+}
+
+func parseCargoComparator(s string) (semv.Range, error) {
+	op, vs := splitOperator(s)
+	p, err := parsePartial(vs)
+	if err != nil {
+		return semv.Range{}, err
+	}
+	if p.any() && op != "" && op != "=" {
+		return semv.Range{}, fmt.Errorf("wildcard used with operator %q", op)
+	}
+	switch op {
+	case "", "^":
+		if p.wildcard {
+			return p.equal(), nil
+		}
+		return p.caret(), nil
+	case "=":
+		return p.equal(), nil
+	case "~":
+		return tilde(p), nil
+	case ">":
+		return p.greaterThan(), nil
+	case ">=":
+		return p.greaterThanOrEqualTo(), nil
+	case "<":
+		return p.lessThan(), nil
+	case "<=":
+		return p.lessThanOrEqualTo(), nil
+	}
+	return semv.Range{}, fmt.Errorf("unknown operator %q", op)
+}
+
+// FormatRange formats rs as a Cargo requirement. Cargo cannot express unions,
+// so rs must contain exactly one range.
+func (cargo) FormatRange(rs semv.RangeSet) (string, error) {
+	if len(rs) != 1 {
+		return "", Unsupported{"cargo", "union of ranges"}
+	}
+	r := rs[0]
+	switch {
+	case isAny(r):
+		return "*", nil
+	case isExact(r):
+		return "=" + complete(*r.MinEqual), nil
+	case isCaret(r):
+		return "^" + complete(*r.MinEqual), nil
+	case isTilde(r):
+		return "~" + complete(*r.MinEqual), nil
+	}
+	return strings.Join(comparators(r, "", complete), ", "), nil
+}
+
+// tilde returns the range of versions greater than or equal to p, which
+// only allow patch-level changes if the minor version is specified, or
+// minor-level changes if it is not. This is the tilde used by npm and Cargo.
+func tilde(p partial) semv.Range {
+	if p.any() {
+		return semv.Range{}
+	}
+	if p.parts == 1 {
+		return semv.GreaterThanOrEqualToAndLessThan(p.v, p.v.IncrementMajor())
+	}
+	return semv.GreaterThanOrEqualToAndLessThan(p.v, p.v.IncrementMinor())
+}
+
+// splitOperator splits a comparator into its operator and version,
+// discarding any whitespace between them.
+func splitOperator(s string) (string, string) {
+	i := strings.IndexFunc(s, func(c rune) bool {
+		return !strings.ContainsRune("=<>!~^", c)
+	})
+	if i == -1 {
+		return s, ""
+	}
+	return s[:i], strings.TrimSpace(s[i:])
+}
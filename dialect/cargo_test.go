@@ -0,0 +1,31 @@
+package dialect
+
+import "testing"
+
+var cargoTests = []dialectTest{
+	{"1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0"}, "^1.2.3"},
+	{"1.2", []string{"1.2.0", "1.9.9"}, []string{"1.1.9", "2.0.0"}, "^1.2.0"},
+	{"1", []string{"1.0.0", "1.9.9"}, []string{"0.9.9", "2.0.0"}, "^1.0.0"},
+	{"0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.2.2", "0.3.0"}, "^0.2.3"},
+	{"0.0.3", []string{"0.0.3"}, []string{"0.0.2", "0.0.4"}, "^0.0.3"},
+	{"^0.0", []string{"0.0.0", "0.0.9"}, []string{"0.1.0"}, "~0.0.0"},
+	{"=1.2.3", []string{"1.2.3"}, []string{"1.2.4"}, "=1.2.3"},
+	{"=1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}, "~1.2.0"},
+	{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0"}, "~1.2.3"},
+	{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}, "^1.0.0"},
+	{"1.2.*", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}, "~1.2.0"},
+	{"*", []string{"0.0.0", "99.0.0"}, nil, "*"},
+	{">=1, <2", []string{"1.0.0", "1.9.9"}, []string{"0.9.9", "2.0.0"}, "^1.0.0"},
+	{">1.2", []string{"1.3.0"}, []string{"1.2.9"}, ">=1.3.0"},
+	{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}, "<1.3.0"},
+	{">= 1.2.0, < 1.5", []string{"1.4.9"}, []string{"1.5.0"}, ">=1.2.0, <1.5.0"},
+	{"^1.2.3-beta.1", []string{"1.2.3-beta.2", "1.2.3"}, []string{"1.2.3-alpha"}, "^1.2.3-beta.1"},
+}
+
+func TestCargo(t *testing.T) {
+	runDialectTests(t, Cargo, cargoTests)
+}
+
+func TestCargo_Invalid(t *testing.T) {
+	runInvalidTests(t, Cargo, []string{"", "1.2.3.4", "a.b", ">*", "1.2 || 2.0", "%1.2"})
+}
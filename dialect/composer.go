@@ -0,0 +1,162 @@
+package dialect
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/samsalisbury/semv"
+)
+
+type composer struct{}
+
+var (
+	composerOr      = regexp.MustCompile(`\s*\|\|?\s*`)
+	composerAnd     = regexp.MustCompile(`\s*,\s*|\s+`)
+	composerHyphen  = regexp.MustCompile(`^(\S+)\s+-\s+(\S+)$`)
+	composerOpSpace = regexp.MustCompile(`([=<>!~^])\s+`)
+	// composerStability maps Composer stability flags to the lowest
+	// prerelease identifier of that stability, so that a lower bound with
+	// that prerelease admits every prerelease at least as stable.
+	composerStability = map[string]string{
+		"dev":   "0",
+		"alpha": "alpha",
+		"beta":  "beta",
+		"rc":    "rc",
+	}
+)
+
+// ParseRange parses a Composer version constraint. Constraints separated by
+// "||" are alternatives, and those separated by spaces or commas must all be
+// satisfied. Stability flags such as "@beta" add the lowest prerelease of that
+// stability to the constraint's bounds, so that prereleases at least that
+// stable satisfy it. Unlike Cargo, a bare version
+// such as "1.2" is an exact constraint, i.e. "1.2.0".
+func (composer) ParseRange(s string) (semv.RangeSet, error) {
+	var rs semv.RangeSet
+	for _, alt := range composerOr.Split(strings.TrimSpace(s), -1) {
+		r, err := parseComposerAnd(alt)
+		if err != nil {
+			return nil, fmt.Errorf("composer: parsing %q: %s", s, err)
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+func parseComposerAnd(s string) (semv.Range, error) {
+	if m := composerHyphen.FindStringSubmatch(s); m != nil {
+		lower, err := parsePartial(m[1])
+		if err != nil {
+			return semv.Range{}, err
+		}
+		upper, err := parsePartial(m[2])
+		if err != nil {
+			return semv.Range{}, err
+		}
+		return lower.greaterThanOrEqualTo().Intersect(upper.lessThanOrEqualTo()), nil
+	}
+	r := semv.Range{}
+	s = composerOpSpace.ReplaceAllString(s, "$1")
+	for _, c := range composerAnd.Split(s, -1) {
+		cr, err := parseComposerComparator(c)
+		if err != nil {
+			return semv.Range{}, err
+		}
+		r = r.Intersect(cr)
+	}
+	return r, nil
+}
+
+func parseComposerComparator(s string) (semv.Range, error) {
+	var stability string
+	if i := strings.LastIndex(s, "@"); i != -1 {
+		stability = strings.ToLower(s[i+1:])
+		s = s[:i]
+	}
+	if strings.HasPrefix(s, "dev-") {
+		return semv.Range{}, Unsupported{"composer", "branch constraint " + s}
+	}
+	op, vs := splitOperator(s)
+	p, err := parsePartial(vs)
+	if err != nil {
+		return semv.Range{}, err
+	}
+	if p.any() && op != "" && op != "=" && op != "==" {
+		return semv.Range{}, fmt.Errorf("wildcard used with operator %q", op)
+	}
+	var r semv.Range
+	switch op {
+	case "", "=", "==":
+		if p.wildcard {
+			r = p.equal()
+		} else {
+			r = semv.EqualTo(p.v)
+		}
+	case "^":
+		r = p.caret()
+	case "~":
+		r = composerTilde(p)
+	case ">":
+		r = p.greaterThan()
+	case ">=":
+		r = p.greaterThanOrEqualTo()
+	case "<":
+		r = p.lessThan()
+	case "<=":
+		r = p.lessThanOrEqualTo()
+	case "!=", "<>":
+		return semv.Range{}, Unsupported{"composer", "operator " + op}
+	default:
+		return semv.Range{}, fmt.Errorf("unknown operator %q", op)
+	}
+	if stability == "" || stability == "stable" {
+		return r, nil
+	}
+	pre, ok := composerStability[stability]
+	if !ok {
+		return semv.Range{}, fmt.Errorf("unknown stability flag %q", stability)
+	}
+	if r.MinEqual != nil && !r.MinEqual.IsPrerelease() {
+		min := withPre(*r.MinEqual, pre)
+		r.MinEqual = &min
+	}
+	if r.Max != nil && !r.Max.IsPrerelease() {
+		max := withPre(*r.Max, pre)
+		r.Max = &max
+	}
+	return r, nil
+}
+
+// composerTilde returns the range for a Composer tilde constraint, in which
+// the last specified component may increase. E.g. ~1.2 means >=1.2.0 <2.0.0,
+// and ~1.2.3 means >=1.2.3 <1.3.0.
+func composerTilde(p partial) semv.Range {
+	if p.parts == 3 {
+		return tilde(p)
+	}
+	return semv.GreaterThanOrEqualToAndLessThan(p.v, p.v.IncrementMajor())
+}
+
+// FormatRange formats rs as a Composer constraint.
+func (composer) FormatRange(rs semv.RangeSet) (string, error) {
+	if len(rs) == 0 {
+		return "", Unsupported{"composer", "empty range set"}
+	}
+	alts := make([]string, len(rs))
+	for i, r := range rs {
+		switch {
+		case isAny(r):
+			alts[i] = "*"
+		case isExact(r):
+			alts[i] = complete(*r.MinEqual)
+		case isCaret(r):
+			alts[i] = "^" + complete(*r.MinEqual)
+		case isTilde(r):
+			alts[i] = "~" + complete(*r.MinEqual)
+		default:
+			alts[i] = strings.Join(comparators(r, "", complete), " ")
+		}
+	}
+	return strings.Join(alts, " || "), nil
+}
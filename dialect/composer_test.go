@@ -0,0 +1,29 @@
+package dialect
+
+import "testing"
+
+var composerTests = []dialectTest{
+	{"1.2", []string{"1.2.0"}, []string{"1.2.1"}, "1.2.0"},
+	{"1.0.*", []string{"1.0.0", "1.0.9"}, []string{"1.1.0"}, "~1.0.0"},
+	{"^1.2", []string{"1.2.0", "1.9.0"}, []string{"2.0.0"}, "^1.2.0"},
+	{"^0.3", []string{"0.3.0", "0.3.9"}, []string{"0.4.0"}, "^0.3.0"},
+	{"~1.2", []string{"1.2.0", "1.9.0"}, []string{"2.0.0"}, "^1.2.0"},
+	{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0"}, "~1.2.3"},
+	{">=1.0 <1.1 || >=1.2", []string{"1.0.5", "1.2.0", "3.0.0"}, []string{"1.1.0"}, "~1.0.0 || >=1.2.0"},
+	{">=1.0,<2.0", []string{"1.5.0"}, []string{"2.0.0"}, "^1.0.0"},
+	{">= 1.0 < 2.0", []string{"1.5.0"}, []string{"2.0.0"}, "^1.0.0"},
+	{"1.0 - 2.0", []string{"1.0.0", "2.0.9"}, []string{"2.1.0"}, ">=1.0.0 <2.1.0"},
+	{"1.0.0 - 2.1.0", []string{"2.1.0"}, []string{"2.1.1"}, ">=1.0.0 <=2.1.0"},
+	{"^1.2 || ~2.0@beta", []string{"1.2.0", "2.0.0-beta", "2.0.0-rc.1", "2.5.0"},
+		[]string{"2.0.0-alpha", "3.0.0"}, "^1.2.0 || ^2.0.0-beta"},
+	{"^1.0 | ^2.0", []string{"1.0.0", "2.0.0"}, []string{"3.0.0"}, "^1.0.0 || ^2.0.0"},
+	{"*", []string{"0.0.0", "9.9.9"}, nil, "*"},
+}
+
+func TestComposer(t *testing.T) {
+	runDialectTests(t, Composer, composerTests)
+}
+
+func TestComposer_Invalid(t *testing.T) {
+	runInvalidTests(t, Composer, []string{"", "dev-master", "!=1.0", "^1.0@unstable", "1.0 ||"})
+}
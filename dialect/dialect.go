@@ -0,0 +1,224 @@
+/*
+Package dialect parses and formats version requirements written in the
+syntax of particular package ecosystems, producing semv ranges.
+
+Each ecosystem has its own default semantics, which are respected here. For
+example, a bare "1.2" means "^1.2" to Cargo, but exactly 1.2.0 to Composer
+and RubyGems.
+*/
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samsalisbury/semv"
+)
+
+type (
+	// Dialect parses and formats version requirements in the syntax of a
+	// particular ecosystem.
+	Dialect interface {
+		// ParseRange parses a requirement string into a RangeSet.
+		ParseRange(s string) (semv.RangeSet, error)
+		// FormatRange formats a RangeSet as a requirement string. It returns
+		// an error if the dialect has no way to express the RangeSet.
+		FormatRange(rs semv.RangeSet) (string, error)
+	}
+	// Unsupported is an error returned when a requirement uses syntax that
+	// is valid in its ecosystem, but which cannot be represented by semv
+	// ranges, or when a RangeSet cannot be expressed in a dialect.
+	Unsupported struct {
+		Dialect, What string
+	}
+	// partial is a possibly incomplete version, as written in a requirement.
+	// E.g. "1.2", or "1.2.*" both have 2 parts.
+	partial struct {
+		v        semv.Version
+		parts    int
+		wildcard bool
+	}
+)
+
+var (
+	// Cargo is the requirement syntax used by Rust's Cargo.
+	Cargo Dialect = cargo{}
+	// Composer is the constraint syntax used by PHP's Composer.
+	Composer Dialect = composer{}
+	// RubyGems is the requirement syntax used by RubyGems and Bundler.
+	RubyGems Dialect = rubyGems{}
+)
+
+func (err Unsupported) Error() string {
+	return fmt.Sprintf("%s: unsupported %s", err.Dialect, err.What)
+}
+
+// parsePartial parses a possibly partial version, where any of the numeric
+// components may be replaced by a wildcard (*, x or X), in which case the
+// remaining components are ignored. A leading v is permitted.
+func parsePartial(s string) (partial, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	core, rest := s, ""
+	if i := strings.IndexAny(s, "-+"); i != -1 {
+		core, rest = s[:i], s[i:]
+	}
+	// The components are kept as digits and parsed by semv.Parse, so that
+	// components too large for an int are handled as they are there.
+	nums := [3]string{"0", "0", "0"}
+	p := partial{}
+	for _, c := range strings.Split(core, ".") {
+		if c == "*" || c == "x" || c == "X" {
+			p.wildcard = true
+			break
+		}
+		if p.parts == 3 {
+			return partial{}, fmt.Errorf("too many components in version %q", s)
+		}
+		if c == "" || strings.Trim(c, "0123456789") != "" {
+			return partial{}, fmt.Errorf("invalid version %q", s)
+		}
+		if c = strings.TrimLeft(c, "0"); c != "" {
+			nums[p.parts] = c
+		}
+		p.parts++
+	}
+	if rest != "" && p.parts != 3 {
+		return partial{}, fmt.Errorf("prerelease or metadata on partial version %q", s)
+	}
+	v, err := semv.Parse(strings.Join(nums[:], ".") + rest)
+	if err != nil {
+		return partial{}, err
+	}
+	p.v = v
+	return p, nil
+}
+
+// any returns true if this partial was a bare wildcard.
+func (p partial) any() bool {
+	return p.parts == 0
+}
+
+// next returns the lowest version greater than every version matching p.
+// E.g. the next of "1.2" is 1.3.0 and the next of "1" is 2.0.0.
+func (p partial) next() semv.Version {
+	v := p.v.MajorMinorPatch()
+	switch p.parts {
+	case 1:
+		return v.IncrementMajor()
+	case 2:
+		return v.IncrementMinor()
+	}
+	return v.IncrementPatch()
+}
+
+// equal returns the range of versions matching p, which for complete
+// versions is an exact match.
+func (p partial) equal() semv.Range {
+	if p.any() {
+		return semv.Range{}
+	}
+	if p.parts == 3 {
+		return semv.EqualTo(p.v)
+	}
+	return semv.GreaterThanOrEqualToAndLessThan(p.v, p.next())
+}
+
+func (p partial) greaterThan() semv.Range {
+	if p.parts == 3 {
+		return semv.GreaterThan(p.v)
+	}
+	return semv.GreaterThanOrEqualTo(p.next())
+}
+
+func (p partial) greaterThanOrEqualTo() semv.Range {
+	return semv.GreaterThanOrEqualTo(p.v)
+}
+
+func (p partial) lessThan() semv.Range {
+	return semv.LessThan(p.v)
+}
+
+func (p partial) lessThanOrEqualTo() semv.Range {
+	if p.parts == 3 {
+		return semv.LessThanOrEqualTo(p.v)
+	}
+	return semv.LessThan(p.next())
+}
+
+// caret returns the range of versions compatible with p, where the left-most
+// non-zero specified component may not change, as used by Cargo and Composer.
+// As with semv.ParseRange, the upper bound keeps any prerelease of p, so that
+// prereleases within the range satisfy it.
+func (p partial) caret() semv.Range {
+	if p.any() {
+		return semv.Range{}
+	}
+	switch {
+	case p.v.Major > 0 || p.parts == 1:
+		return semv.GreaterThanOrEqualToAndLessThan(p.v, p.v.IncrementMajor())
+	case p.v.Minor > 0 || p.parts == 2:
+		return semv.GreaterThanOrEqualToAndLessThan(p.v, p.v.IncrementMinor())
+	}
+	return semv.GreaterThanOrEqualToAndLessThan(p.v, p.v.IncrementPatch())
+}
+
+// isCaret returns true if r is exactly the caret range of its lower bound.
+func isCaret(r semv.Range) bool {
+	if r.MinEqual == nil || r.Max == nil || r.Min != nil || r.MaxEqual != nil {
+		return false
+	}
+	return r.Equals(partial{v: *r.MinEqual, parts: 3}.caret())
+}
+
+// isTilde returns true if r is the range of versions greater than or equal
+// to its lower bound, and less than the next minor version.
+func isTilde(r semv.Range) bool {
+	if r.MinEqual == nil || r.Max == nil || r.Min != nil || r.MaxEqual != nil {
+		return false
+	}
+	return r.Max.Equals(r.MinEqual.IncrementMinor())
+}
+
+// isExact returns true if r is satisfied by a single version only.
+func isExact(r semv.Range) bool {
+	return r.MinEqual != nil && r.MaxEqual != nil && r.Min == nil && r.Max == nil &&
+		r.MinEqual.Equals(*r.MaxEqual)
+}
+
+// isAny returns true if r has no bounds at all.
+func isAny(r semv.Range) bool {
+	return r.Min == nil && r.MinEqual == nil && r.Max == nil && r.MaxEqual == nil
+}
+
+// comparators returns the bounds of r as a list of comparator strings, with
+// sep placed between each operator and its version, and versions formatted
+// using format.
+func comparators(r semv.Range, sep string, format func(semv.Version) string) []string {
+	var out []string
+	if r.Min != nil {
+		out = append(out, ">"+sep+format(*r.Min))
+	}
+	if r.MinEqual != nil {
+		out = append(out, ">="+sep+format(*r.MinEqual))
+	}
+	if r.Max != nil {
+		out = append(out, "<"+sep+format(*r.Max))
+	}
+	if r.MaxEqual != nil {
+		out = append(out, "<="+sep+format(*r.MaxEqual))
+	}
+	return out
+}
+
+// withPre returns a copy of v with its prerelease set to pre. Unlike
+// Version.SetPre, the copy is always formatted in full by String.
+func withPre(v semv.Version, pre string) semv.Version {
+	v = v.SetPre(pre)
+	v.SetFormat("")
+	return v
+}
+
+// complete formats a version in full, with any prerelease and metadata.
+func complete(v semv.Version) string {
+	return v.Format(semv.Complete)
+}
@@ -0,0 +1,96 @@
+package dialect
+
+import (
+	"testing"
+
+	"github.com/samsalisbury/semv"
+)
+
+// dialectTest describes a requirement, some versions which should and should
+// not satisfy it, and the string it should be formatted back to.
+type dialectTest struct {
+	input       string
+	satisfied   []string
+	unsatisfied []string
+	formatted   string
+}
+
+func runDialectTests(t *testing.T, d Dialect, tests []dialectTest) {
+	for _, test := range tests {
+		rs, err := d.ParseRange(test.input)
+		if err != nil {
+			t.Errorf("parsing %q: unexpected error: %s", test.input, err)
+			continue
+		}
+		for _, vs := range test.satisfied {
+			if !rs.SatisfiedBy(semv.MustParse(vs)) {
+				t.Errorf("expected %q (%s) to be satisfied by %q", test.input, rs, vs)
+			}
+		}
+		for _, vs := range test.unsatisfied {
+			if rs.SatisfiedBy(semv.MustParse(vs)) {
+				t.Errorf("expected %q (%s) not to be satisfied by %q", test.input, rs, vs)
+			}
+		}
+		formatted, err := d.FormatRange(rs)
+		if err != nil {
+			t.Errorf("formatting %q: unexpected error: %s", test.input, err)
+			continue
+		}
+		if formatted != test.formatted {
+			t.Errorf("formatting %q gave %q; want %q", test.input, formatted, test.formatted)
+		}
+		reparsed, err := d.ParseRange(formatted)
+		if err != nil {
+			t.Errorf("reparsing %q: unexpected error: %s", formatted, err)
+			continue
+		}
+		if len(reparsed) != len(rs) {
+			t.Errorf("reparsing %q gave %s; want %s", formatted, reparsed, rs)
+			continue
+		}
+		for i := range rs {
+			if !reparsed[i].Equals(rs[i]) {
+				t.Errorf("reparsing %q gave %s; want %s", formatted, reparsed, rs)
+			}
+		}
+	}
+}
+
+func runInvalidTests(t *testing.T, d Dialect, inputs []string) {
+	for _, input := range inputs {
+		if rs, err := d.ParseRange(input); err == nil {
+			t.Errorf("expected error parsing %q; got %s", input, rs)
+		}
+	}
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	union := semv.RangeSet{semv.MustParseRange("^1.0.0"), semv.MustParseRange("^3.0.0")}
+	for _, d := range []Dialect{Cargo, RubyGems} {
+		_, err := d.FormatRange(union)
+		if _, ok := err.(Unsupported); !ok {
+			t.Errorf("got error %v formatting union; want Unsupported", err)
+		}
+	}
+}
+
+func TestBigComponents(t *testing.T) {
+	const big = "20261016123045999000"
+	tests := []struct {
+		d    Dialect
+		test dialectTest
+	}{
+		{Cargo, dialectTest{"1.0." + big,
+			[]string{"1.0." + big, "1.9.0"}, []string{"1.0.20261016123045998999", "2.0.0"}, "^1.0." + big}},
+		{Cargo, dialectTest{"=" + big + ".1",
+			[]string{big + ".1.0", big + ".1.9"}, []string{big + ".2.0", "20261016123045999001.1.0"}, "~" + big + ".1.0"}},
+		{Composer, dialectTest{big + ".*",
+			[]string{big + ".0.0", big + ".9.9"}, []string{"20261016123045999001.0.0", "1.0.0"}, "^" + big + ".0.0"}},
+		{RubyGems, dialectTest{"~> 1.0." + big,
+			[]string{"1.0." + big, "1.0.20261016123045999001"}, []string{"1.1.0"}, "~> 1.0." + big}},
+	}
+	for _, test := range tests {
+		runDialectTests(t, test.d, []dialectTest{test.test})
+	}
+}
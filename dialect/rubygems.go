@@ -0,0 +1,128 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samsalisbury/semv"
+)
+
+type rubyGems struct{}
+
+// ParseRange parses a RubyGems requirement, which is a comma-separated list of
+// requirements, all of which must be satisfied. A version with no operator is
+// an exact requirement. The pessimistic operator "~>" allows the last
+// specified component to increase, so "~> 2.2" means ">= 2.2, < 3.0", and
+// "~> 2.2.0" means ">= 2.2.0, < 2.3.0".
+//
+// RubyGems prerelease versions such as "1.0.0.beta1", where the prerelease is
+// separated by a dot, are read as the semver prerelease "1.0.0-beta1".
+func (rubyGems) ParseRange(s string) (semv.RangeSet, error) {
+	r := semv.Range{}
+	for _, c := range strings.Split(s, ",") {
+		cr, err := parseGemRequirement(strings.TrimSpace(c))
+		if err != nil {
+			return nil, fmt.Errorf("rubygems: parsing %q: %s", s, err)
+		}
+		r = r.Intersect(cr)
+	}
+	return semv.RangeSet{r}, nil
+}
+
+func parseGemRequirement(s string) (semv.Range, error) {
+	op, vs := splitOperator(s)
+	p, err := parseGemVersion(vs)
+	if err != nil {
+		return semv.Range{}, err
+	}
+	switch op {
+	case "", "=":
+		return semv.EqualTo(p.v), nil
+	case "~>":
+		return pessimistic(p), nil
+	case ">":
+		return semv.GreaterThan(p.v), nil
+	case ">=":
+		return semv.GreaterThanOrEqualTo(p.v), nil
+	case "<":
+		return semv.LessThan(p.v), nil
+	case "<=":
+		return semv.LessThanOrEqualTo(p.v), nil
+	case "!=":
+		return semv.Range{}, Unsupported{"rubygems", "operator !="}
+	}
+	return semv.Range{}, fmt.Errorf("unknown operator %q", op)
+}
+
+// parseGemVersion parses a RubyGems version, in which missing components are
+// zero, and any components after the first one containing a letter form the
+// prerelease.
+func parseGemVersion(s string) (partial, error) {
+	parts := strings.Split(s, ".")
+	for i, part := range parts {
+		if strings.IndexFunc(part, isLetter) == -1 {
+			continue
+		}
+		if i == 0 || i > 3 {
+			return partial{}, fmt.Errorf("invalid version %q", s)
+		}
+		p, err := parsePartial(strings.Join(parts[:i], "."))
+		if err != nil {
+			return partial{}, err
+		}
+		p.v = withPre(p.v, strings.Join(parts[i:], "."))
+		return p, nil
+	}
+	if len(parts) > 3 {
+		return partial{}, fmt.Errorf("too many components in version %q", s)
+	}
+	return parsePartial(s)
+}
+
+func isLetter(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// pessimistic returns the range for the RubyGems "~>" operator.
+func pessimistic(p partial) semv.Range {
+	if p.parts == 3 {
+		return semv.GreaterThanOrEqualToAndLessThan(p.v, p.v.IncrementMinor())
+	}
+	return semv.GreaterThanOrEqualToAndLessThan(p.v, p.v.IncrementMajor())
+}
+
+// FormatRange formats rs as a RubyGems requirement. RubyGems cannot express
+// unions, so rs must contain exactly one range.
+func (rubyGems) FormatRange(rs semv.RangeSet) (string, error) {
+	if len(rs) != 1 {
+		return "", Unsupported{"rubygems", "union of ranges"}
+	}
+	r := rs[0]
+	switch {
+	case isAny(r):
+		return ">= 0", nil
+	case isExact(r):
+		return "= " + gemVersion(*r.MinEqual), nil
+	case isTilde(r):
+		return "~> " + gemVersion(*r.MinEqual), nil
+	case r.MinEqual != nil && r.Max != nil && r.Min == nil && r.MaxEqual == nil &&
+		r.MinEqual.Patch == 0 && r.Max.Equals(r.MinEqual.IncrementMajor()):
+		return "~> " + gemFormat(*r.MinEqual, semv.MajorMinor), nil
+	}
+	return strings.Join(comparators(r, " ", gemVersion), ", "), nil
+}
+
+// gemVersion formats v in RubyGems style, where the prerelease is separated
+// from the version by a dot.
+func gemVersion(v semv.Version) string {
+	return gemFormat(v, semv.MajorMinorPatch)
+}
+
+// gemFormat is like gemVersion, but formats the numeric components of v
+// using format.
+func gemFormat(v semv.Version, format string) string {
+	if v.IsPrerelease() {
+		return v.Format(format) + "." + v.Pre
+	}
+	return v.Format(format)
+}
@@ -0,0 +1,23 @@
+package dialect
+
+import "testing"
+
+var rubyGemsTests = []dialectTest{
+	{"1.2", []string{"1.2.0"}, []string{"1.2.1"}, "= 1.2.0"},
+	{"= 1.2.3", []string{"1.2.3"}, []string{"1.2.4"}, "= 1.2.3"},
+	{"~> 2.2", []string{"2.2.0", "2.9.9"}, []string{"2.1.9", "3.0.0"}, "~> 2.2"},
+	{"~> 2.2.0", []string{"2.2.0", "2.2.9"}, []string{"2.3.0"}, "~> 2.2.0"},
+	{"~> 2", []string{"2.0.0", "2.9.0"}, []string{"3.0.0"}, "~> 2.0"},
+	{">= 1.0, < 3", []string{"1.0.0", "2.9.9"}, []string{"3.0.0"}, ">= 1.0.0, < 3.0.0"},
+	{"~> 2.2, >= 2.2.5", []string{"2.2.5", "2.9.0"}, []string{"2.2.4"}, ">= 2.2.5, < 3.0.0"},
+	{"~> 1.0.0.beta1", []string{"1.0.0-beta1", "1.0.0-beta2", "1.0.5"}, []string{"1.1.0"}, "~> 1.0.0.beta1"},
+	{">= 0", []string{"0.0.0", "9.0.0"}, nil, ">= 0.0.0"},
+}
+
+func TestRubyGems(t *testing.T) {
+	runDialectTests(t, RubyGems, rubyGemsTests)
+}
+
+func TestRubyGems_Invalid(t *testing.T) {
+	runInvalidTests(t, RubyGems, []string{"", "!= 1.0", "=> 1.0", "1.2.3.4", "beta"})
+}
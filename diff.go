@@ -0,0 +1,146 @@
+package semv
+
+import "fmt"
+
+type (
+	// Change is the most significant part of a version which differs between
+	// two versions, as reported by Diff.
+	Change int
+	// Transition describes whether the versions compared by Diff are
+	// prereleases.
+	Transition int
+	// VersionDiff describes the change from one version to another. See
+	// Diff.
+	VersionDiff struct {
+		From, To Version
+		// Change is the most significant part of the version which differs.
+		Change Change
+		// Downgrade is true if To has lower precedence than From.
+		Downgrade bool
+		// Transition describes whether From and To are prereleases, e.g.
+		// PrereleaseToStable for a release candidate being released.
+		Transition Transition
+		// Breaking is true if the change may be incompatible: a major change,
+		// or a minor change whilst the major version is 0, since semver
+		// treats 0.y.z versions as unstable.
+		Breaking bool
+	}
+)
+
+const (
+	// NoChange means the versions are identical, including their metadata.
+	NoChange Change = iota
+	// MetadataChange means only the build metadata differs.
+	MetadataChange
+	// PrereleaseChange means the major, minor and patch components are equal,
+	// but the prerelease differs, e.g. "1.0.0-rc.1" and "1.0.0".
+	PrereleaseChange
+	// PatchChange means the patch component is the first which differs.
+	PatchChange
+	// MinorChange means the minor component is the first which differs.
+	MinorChange
+	// MajorChange means the major component differs.
+	MajorChange
+)
+
+const (
+	// StableToStable means neither version is a prerelease.
+	StableToStable Transition = iota
+	// PrereleaseToStable means a prerelease is being replaced by a stable
+	// version, e.g. when "1.0.0-rc.1" is released as "1.0.0".
+	PrereleaseToStable
+	// StableToPrerelease means a stable version is being replaced by a
+	// prerelease.
+	StableToPrerelease
+	// PrereleaseToPrerelease means both versions are prereleases.
+	PrereleaseToPrerelease
+)
+
+func (c Change) String() string {
+	switch c {
+	case NoChange:
+		return "none"
+	case MetadataChange:
+		return "metadata"
+	case PrereleaseChange:
+		return "prerelease"
+	case PatchChange:
+		return "patch"
+	case MinorChange:
+		return "minor"
+	case MajorChange:
+		return "major"
+	}
+	return fmt.Sprintf("Change(%d)", int(c))
+}
+
+func (t Transition) String() string {
+	switch t {
+	case StableToStable:
+		return "stable to stable"
+	case PrereleaseToStable:
+		return "prerelease to stable"
+	case StableToPrerelease:
+		return "stable to prerelease"
+	case PrereleaseToPrerelease:
+		return "prerelease to prerelease"
+	}
+	return fmt.Sprintf("Transition(%d)", int(t))
+}
+
+// Diff classifies the change from version a to version b, e.g. for deciding
+// whether an upgrade can be applied automatically:
+//
+//	Diff(MustParse("1.2.3"), MustParse("1.3.0")).Change == MinorChange
+//	Diff(MustParse("0.2.3"), MustParse("0.3.0")).Breaking == true
+//	Diff(MustParse("2.0.0-rc.1"), MustParse("2.0.0")).Transition == PrereleaseToStable
+//
+// Precedence is decided as by Less, so a change of metadata alone is neither
+// an upgrade nor a downgrade.
+func Diff(a, b Version) VersionDiff {
+	d := VersionDiff{From: a, To: b, Downgrade: b.Less(a)}
+	switch {
+	case a.IsPrerelease() && b.IsPrerelease():
+		d.Transition = PrereleaseToPrerelease
+	case a.IsPrerelease():
+		d.Transition = PrereleaseToStable
+	case b.IsPrerelease():
+		d.Transition = StableToPrerelease
+	}
+	switch {
+	case !a.MMPEqual(b):
+		d.Change = PatchChange
+		if compareComponents(a.Major, a.bigMajor, b.Major, b.bigMajor) != 0 {
+			d.Change = MajorChange
+		} else if compareComponents(a.Minor, a.bigMinor, b.Minor, b.bigMinor) != 0 {
+			d.Change = MinorChange
+		}
+	case a.Pre != b.Pre:
+		d.Change = PrereleaseChange
+	case a.Meta != b.Meta:
+		d.Change = MetadataChange
+	}
+	d.Breaking = d.Change == MajorChange || d.Change == MinorChange && a.Major == 0
+	return d
+}
+
+// IsUpgrade returns true if To has higher precedence than From.
+func (d VersionDiff) IsUpgrade() bool {
+	return d.From.Less(d.To)
+}
+
+// String describes the change, e.g. "minor upgrade from 1.2.3 to 1.3.0", or
+// "breaking major downgrade from 2.0.0 to 1.9.0".
+func (d VersionDiff) String() string {
+	direction := "change"
+	if d.IsUpgrade() {
+		direction = "upgrade"
+	} else if d.Downgrade {
+		direction = "downgrade"
+	}
+	breaking := ""
+	if d.Breaking {
+		breaking = "breaking "
+	}
+	return fmt.Sprintf("%s%s %s from %s to %s", breaking, d.Change, direction, d.From, d.To)
+}
@@ -0,0 +1,57 @@
+package semv
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		a, b       string
+		change     Change
+		downgrade  bool
+		transition Transition
+		breaking   bool
+	}{
+		{"1.2.3", "1.2.3", NoChange, false, StableToStable, false},
+		{"1.2", "1.2.0", NoChange, false, StableToStable, false},
+		{"1.2.3+a", "1.2.3+b", MetadataChange, false, StableToStable, false},
+		{"1.2.3-rc.1", "1.2.3-rc.2", PrereleaseChange, false, PrereleaseToPrerelease, false},
+		{"1.2.3-rc.2", "1.2.3-rc.1", PrereleaseChange, true, PrereleaseToPrerelease, false},
+		{"2.0.0-rc.1", "2.0.0", PrereleaseChange, false, PrereleaseToStable, false},
+		{"2.0.0", "2.0.0-rc.1", PrereleaseChange, true, StableToPrerelease, false},
+		{"1.2.3", "1.2.4", PatchChange, false, StableToStable, false},
+		{"1.2.4", "1.2.3", PatchChange, true, StableToStable, false},
+		{"1.2.3", "1.3.0", MinorChange, false, StableToStable, false},
+		{"1.3.0", "1.2.9", MinorChange, true, StableToStable, false},
+		{"1.2.3", "2.0.0-beta", MajorChange, false, StableToPrerelease, true},
+		{"2.0.0", "1.9.9", MajorChange, true, StableToStable, true},
+		{"0.2.3", "0.2.4", PatchChange, false, StableToStable, false},
+		{"0.2.3", "0.3.0", MinorChange, false, StableToStable, true},
+		{"0.9.0", "1.0.0", MajorChange, false, StableToStable, true},
+		{"1.0.99999999999999999999", "1.0.100000000000000000000", PatchChange, false, StableToStable, false},
+		{"99999999999999999999.0.0", "100000000000000000000.0.0", MajorChange, false, StableToStable, true},
+	}
+	for _, test := range tests {
+		d := Diff(MustParse(test.a), MustParse(test.b))
+		if d.Change != test.change || d.Downgrade != test.downgrade || d.Transition != test.transition || d.Breaking != test.breaking {
+			t.Errorf("Diff(%s, %s): got %s, %t, %s, %t; want %s, %t, %s, %t", test.a, test.b,
+				d.Change, d.Downgrade, d.Transition, d.Breaking,
+				test.change, test.downgrade, test.transition, test.breaking)
+		}
+		if d.Change > MetadataChange && d.IsUpgrade() == d.Downgrade {
+			t.Errorf("Diff(%s, %s): got IsUpgrade() == %t and Downgrade == %t", test.a, test.b, d.IsUpgrade(), d.Downgrade)
+		}
+	}
+}
+
+func TestVersionDiff_String(t *testing.T) {
+	tests := map[[2]string]string{
+		{"1.2.3", "1.3.0"}:     "minor upgrade from 1.2.3 to 1.3.0",
+		{"2.0.0", "1.9.0"}:     "breaking major downgrade from 2.0.0 to 1.9.0",
+		{"0.1.0", "0.2.0"}:     "breaking minor upgrade from 0.1.0 to 0.2.0",
+		{"1.0.0+a", "1.0.0+b"}: "metadata change from 1.0.0+a to 1.0.0+b",
+	}
+	for versions, expected := range tests {
+		if actual := Diff(MustParse(versions[0]), MustParse(versions[1])).String(); actual != expected {
+			t.Errorf("got %q; want %q", actual, expected)
+		}
+	}
+}
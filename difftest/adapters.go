@@ -0,0 +1,103 @@
+package difftest
+
+import (
+	"strings"
+
+	masterminds "github.com/Masterminds/semver/v3"
+	"github.com/samsalisbury/semv"
+	xmod "golang.org/x/mod/semver"
+)
+
+var (
+	// Semv tests semv, parsing versions with Parse and ranges with
+	// ParseRangeSet.
+	Semv Implementation = semvImpl{semv.Parse, "semv"}
+	// SemvExact tests semv, parsing versions with ParseExactSemver2 and
+	// ranges with ParseRangeSet.
+	SemvExact Implementation = semvImpl{semv.ParseExactSemver2, "semv-exact"}
+	// XMod tests golang.org/x/mod/semver. Since it requires versions to
+	// start with "v", one is added to versions which do not already have
+	// one. It has no ranges.
+	XMod Implementation = xmodImpl{}
+	// Masterminds tests github.com/Masterminds/semver/v3, parsing versions
+	// with NewVersion and ranges with NewConstraint.
+	Masterminds Implementation = mastermindsImpl{}
+)
+
+type (
+	semvImpl struct {
+		parse func(string) (semv.Version, error)
+		name  string
+	}
+	xmodImpl        struct{}
+	mastermindsImpl struct{}
+)
+
+func (impl semvImpl) Name() string { return impl.name }
+
+func (impl semvImpl) Valid(s string) bool {
+	_, err := impl.parse(s)
+	return err == nil
+}
+
+func (impl semvImpl) Compare(a, b string) int {
+	va, _ := impl.parse(a)
+	vb, _ := impl.parse(b)
+	switch {
+	case va.Less(vb):
+		return -1
+	case vb.Less(va):
+		return 1
+	}
+	return 0
+}
+
+func (impl semvImpl) Satisfies(rng, version string) (bool, bool) {
+	rs, err := semv.ParseRangeSet(rng)
+	if err != nil {
+		return false, false
+	}
+	v, _ := impl.parse(version)
+	return rs.SatisfiedBy(v), true
+}
+
+func (xmodImpl) Name() string { return "x/mod" }
+
+// xmodVersion adds the "v" prefix required by x/mod/semver to s.
+func xmodVersion(s string) string {
+	if strings.HasPrefix(s, "v") {
+		return s
+	}
+	return "v" + s
+}
+
+func (xmodImpl) Valid(s string) bool {
+	return xmod.IsValid(xmodVersion(s))
+}
+
+func (xmodImpl) Compare(a, b string) int {
+	return xmod.Compare(xmodVersion(a), xmodVersion(b))
+}
+
+func (xmodImpl) Satisfies(rng, version string) (bool, bool) {
+	return false, false
+}
+
+func (mastermindsImpl) Name() string { return "masterminds" }
+
+func (mastermindsImpl) Valid(s string) bool {
+	_, err := masterminds.NewVersion(s)
+	return err == nil
+}
+
+func (mastermindsImpl) Compare(a, b string) int {
+	return masterminds.MustParse(a).Compare(masterminds.MustParse(b))
+}
+
+func (mastermindsImpl) Satisfies(rng, version string) (bool, bool) {
+	c, err := masterminds.NewConstraint(rng)
+	if err != nil {
+		return false, false
+	}
+	return c.Check(masterminds.MustParse(version)), true
+}
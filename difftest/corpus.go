@@ -0,0 +1,111 @@
+package difftest
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// Corpus is the input to Run.
+type Corpus struct {
+	Versions, Ranges []string
+}
+
+var (
+	corpusNumbers = []string{"0", "0", "1", "1", "2", "10", "01", "18446744073709551616"}
+	corpusIdents  = []string{"alpha", "beta", "rc", "0", "1", "2", "11", "01", "x-y", "-", "1a"}
+	corpusOps     = []string{"", "=", ">", ">=", "<", "<=", "~", "^"}
+)
+
+// Generate returns a corpus of n versions and n ranges, generated from seed,
+// so that the same seed always gives the same corpus. Most versions are
+// valid semver 2.0.0, but some have too few or too many components, a "v"
+// prefix, leading zeros, or empty identifiers, since that is where
+// implementations tend to disagree. Ranges are made of one or two
+// comparators using the operators =, >, >=, <, <=, ~ and ^, hyphen ranges,
+// and unions of those using ||.
+func Generate(seed int64, n int) Corpus {
+	g := generator{rand.New(rand.NewSource(seed))}
+	c := Corpus{}
+	for i := 0; i < n; i++ {
+		c.Versions = append(c.Versions, g.version())
+	}
+	for i := 0; i < n; i++ {
+		c.Ranges = append(c.Ranges, g.rangeSet())
+	}
+	return c
+}
+
+type generator struct {
+	*rand.Rand
+}
+
+func (g generator) pick(s []string) string {
+	return s[g.Intn(len(s))]
+}
+
+// chance returns true with probability 1/n.
+func (g generator) chance(n int) bool {
+	return g.Intn(n) == 0
+}
+
+func (g generator) version() string {
+	parts := 3
+	if g.chance(5) {
+		parts = 1 + g.Intn(4)
+	}
+	nums := make([]string, parts)
+	for i := range nums {
+		nums[i] = g.pick(corpusNumbers)
+	}
+	s := strings.Join(nums, ".")
+	if g.chance(8) {
+		s = "v" + s
+	}
+	if g.chance(3) {
+		s += "-" + g.identifiers()
+	}
+	if g.chance(5) {
+		s += "+" + g.identifiers()
+	}
+	return s
+}
+
+func (g generator) identifiers() string {
+	ids := make([]string, 1+g.Intn(3))
+	for i := range ids {
+		if !g.chance(30) {
+			ids[i] = g.pick(corpusIdents)
+		}
+	}
+	return strings.Join(ids, ".")
+}
+
+// simpleVersion returns a valid semver 2.0.0 version, which may have a
+// prerelease but no metadata, as used in ranges.
+func (g generator) simpleVersion() string {
+	s := g.pick(corpusNumbers[:6]) + "." + g.pick(corpusNumbers[:6]) + "." + g.pick(corpusNumbers[:6])
+	if g.chance(4) {
+		s += "-" + g.pick(corpusIdents[:7])
+	}
+	return s
+}
+
+func (g generator) comparator() string {
+	return g.pick(corpusOps) + g.simpleVersion()
+}
+
+func (g generator) rangeSet() string {
+	var s string
+	switch {
+	case g.chance(6):
+		s = g.simpleVersion() + " - " + g.simpleVersion()
+	case g.chance(3):
+		s = g.comparator() + " " + g.comparator()
+	default:
+		s = g.comparator()
+	}
+	if g.chance(8) {
+		s += " || " + g.rangeSet()
+	}
+	return s
+}
@@ -0,0 +1,330 @@
+/*
+Package difftest runs a corpus of versions and ranges through several semver
+implementations, and reports where they disagree about which versions are
+valid, how versions are ordered, and which versions satisfy which ranges.
+
+Besides semv itself, adapters are provided for golang.org/x/mod/semver and
+github.com/Masterminds/semver/v3, which are vendored in this package's vendor
+directory at v0.40.0 and v3.5.0 respectively, so that the harness always
+tests the same code. Some disagreements are intentional, e.g. semv accepts
+"1.2" where x/mod/semver requires "v1.2"; these are described by Divergences,
+so that only new disagreements need attention.
+*/
+package difftest
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+type (
+	// Implementation is a semver implementation under test. All of its
+	// methods take versions and ranges as strings, so that each
+	// implementation can apply its own parsing rules.
+	Implementation interface {
+		// Name returns a short name identifying the implementation.
+		Name() string
+		// Valid returns true if the implementation accepts s as a version.
+		Valid(s string) bool
+		// Compare returns -1, 0 or 1 if version a has lower, equal or
+		// higher precedence than version b. It is only called with versions
+		// for which Valid returns true.
+		Compare(a, b string) int
+		// Satisfies returns true if version satisfies the range. If the
+		// implementation does not accept the range, or has no ranges, ok is
+		// false. It is only called with versions for which Valid returns
+		// true.
+		Satisfies(rng, version string) (satisfied, ok bool)
+	}
+	// Kind is a kind of check made by Run.
+	Kind int
+	// Disagreement is a check for which the implementations gave different
+	// results.
+	Disagreement struct {
+		Kind Kind
+		// Inputs are the version for Acceptance checks, the two versions for
+		// Ordering checks, or the range and version for Satisfaction checks.
+		Inputs []string
+		// Results are the result of each implementation, in the order they
+		// were passed to Run.
+		Results []Result
+	}
+	// Result is the result of a check made using one implementation.
+	Result struct {
+		Implementation string
+		// Value is e.g. "true" or "-1", or "n/a" if the check did not apply
+		// to the implementation.
+		Value string
+	}
+	// Report is the outcome of Run.
+	Report struct {
+		// Implementations are the names of the implementations, in the order
+		// they were passed to Run.
+		Implementations []string
+		// Checked is the number of checks of each kind made, for which at
+		// least two implementations gave a result.
+		Checked map[Kind]int
+		// Disagreements are the checks for which the implementations
+		// disagreed, in corpus order.
+		Disagreements []Disagreement
+		// Divergences, if set, are used by WriteTo to say which divergence
+		// explains each disagreement.
+		Divergences []Divergence
+	}
+	// Divergence is a known difference in behaviour between implementations,
+	// which is either intentional or not worth fixing.
+	Divergence struct {
+		// Name briefly identifies the divergence.
+		Name string
+		// Reason explains why the implementations differ.
+		Reason string
+		// Explains returns true if the divergence accounts for result r in d
+		// differing from d.Reference().
+		Explains func(d Disagreement, r Result) bool
+	}
+)
+
+const (
+	// Acceptance checks whether a version is valid.
+	Acceptance Kind = iota
+	// Ordering checks the precedence of two versions.
+	Ordering
+	// Satisfaction checks whether a version satisfies a range.
+	Satisfaction
+)
+
+// notApplicable is the result of a check which does not apply to an
+// implementation.
+const notApplicable = "n/a"
+
+func (k Kind) String() string {
+	switch k {
+	case Acceptance:
+		return "acceptance"
+	case Ordering:
+		return "ordering"
+	case Satisfaction:
+		return "satisfaction"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Run makes every check over the corpus using each implementation, and
+// reports the checks for which they disagree. Versions are checked for
+// acceptance, every pair of versions is checked for ordering, and every
+// version is checked against every range. An implementation only takes part
+// in an ordering or satisfaction check if it accepts the versions involved.
+func Run(c Corpus, impls ...Implementation) Report {
+	r := Report{Checked: map[Kind]int{}}
+	valid := make([][]bool, len(impls))
+	for i, impl := range impls {
+		r.Implementations = append(r.Implementations, impl.Name())
+		valid[i] = make([]bool, len(c.Versions))
+		for j, v := range c.Versions {
+			valid[i][j] = impl.Valid(v)
+		}
+	}
+	for j, v := range c.Versions {
+		r.check(Acceptance, []string{v}, func(i int) string {
+			return fmt.Sprint(valid[i][j])
+		})
+	}
+	for j, a := range c.Versions {
+		for k := j + 1; k < len(c.Versions); k++ {
+			r.check(Ordering, []string{a, c.Versions[k]}, func(i int) string {
+				if !valid[i][j] || !valid[i][k] {
+					return notApplicable
+				}
+				return fmt.Sprint(impls[i].Compare(a, c.Versions[k]))
+			})
+		}
+	}
+	for _, rng := range c.Ranges {
+		for j, v := range c.Versions {
+			r.check(Satisfaction, []string{rng, v}, func(i int) string {
+				if !valid[i][j] {
+					return notApplicable
+				}
+				satisfied, ok := impls[i].Satisfies(rng, v)
+				if !ok {
+					return notApplicable
+				}
+				return fmt.Sprint(satisfied)
+			})
+		}
+	}
+	return r
+}
+
+// check makes a single check, using result to get the result of each
+// implementation by index, and records a Disagreement if the results which
+// apply differ.
+func (r *Report) check(kind Kind, inputs []string, result func(i int) string) {
+	results := make([]string, len(r.Implementations))
+	first, applied, agreed := "", 0, true
+	for i := range results {
+		results[i] = result(i)
+		switch {
+		case results[i] == notApplicable:
+			continue
+		case applied == 0:
+			first = results[i]
+		case results[i] != first:
+			agreed = false
+		}
+		applied++
+	}
+	if applied < 2 {
+		return
+	}
+	r.Checked[kind]++
+	if agreed {
+		return
+	}
+	d := Disagreement{Kind: kind, Inputs: inputs}
+	for i, name := range r.Implementations {
+		d.Results = append(d.Results, Result{name, results[i]})
+	}
+	r.Disagreements = append(r.Disagreements, d)
+}
+
+// Applies returns true if the check applied to the implementation.
+func (r Result) Applies() bool {
+	return r.Value != notApplicable
+}
+
+// Result returns the value of the result of the named implementation, or
+// "n/a" if the check did not apply to it.
+func (d Disagreement) Result(name string) string {
+	for _, r := range d.Results {
+		if r.Implementation == name {
+			return r.Value
+		}
+	}
+	return notApplicable
+}
+
+// Reference returns the result of the first implementation the check
+// applied to, which the other results are explained relative to.
+func (d Disagreement) Reference() Result {
+	for _, r := range d.Results {
+		if r.Applies() {
+			return r
+		}
+	}
+	return Result{}
+}
+
+// Explain returns the divergences which explain the results in d which
+// differ from d.Reference(), and the results which none of them explain.
+func (d Disagreement) Explain(divergences []Divergence) (explainedBy []*Divergence, unexplained []Result) {
+	ref := d.Reference()
+	for _, r := range d.Results {
+		if !r.Applies() || r.Value == ref.Value {
+			continue
+		}
+		var found *Divergence
+		for i := range divergences {
+			if divergences[i].Explains(d, r) {
+				found = &divergences[i]
+				break
+			}
+		}
+		if found == nil {
+			unexplained = append(unexplained, r)
+		} else if !slices.Contains(explainedBy, found) {
+			explainedBy = append(explainedBy, found)
+		}
+	}
+	return explainedBy, unexplained
+}
+
+// Unexplained returns the disagreements in which any result is not
+// explained by the divergences.
+func (r Report) Unexplained(divergences []Divergence) []Disagreement {
+	var out []Disagreement
+	for _, d := range r.Disagreements {
+		if _, unexplained := d.Explain(divergences); len(unexplained) != 0 {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// WriteTo writes the report to w as a table with a row for each
+// disagreement, preceded by a summary of the number of checks made.
+func (r Report) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	for _, k := range []Kind{Acceptance, Ordering, Satisfaction} {
+		disagreed := 0
+		for _, d := range r.Disagreements {
+			if d.Kind == k {
+				disagreed++
+			}
+		}
+		fmt.Fprintf(cw, "%s: %d checks, %d disagreements\n", k, r.Checked[k], disagreed)
+	}
+	if len(r.Disagreements) != 0 {
+		fmt.Fprintln(cw)
+		tw := tabwriter.NewWriter(cw, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "KIND\tINPUTS\t%s", strings.ToUpper(strings.Join(r.Implementations, "\t")))
+		if r.Divergences != nil {
+			fmt.Fprint(tw, "\tEXPLAINED BY")
+		}
+		fmt.Fprintln(tw)
+		for _, d := range r.Disagreements {
+			fmt.Fprintf(tw, "%s\t%s", d.Kind, strings.Join(quote(d.Inputs), " "))
+			for _, result := range d.Results {
+				fmt.Fprintf(tw, "\t%s", result.Value)
+			}
+			if r.Divergences != nil {
+				fmt.Fprintf(tw, "\t%s", explanation(d, r.Divergences))
+			}
+			fmt.Fprintln(tw)
+		}
+		tw.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// explanation returns the names of the divergences which explain d, and
+// "UNEXPLAINED" if they do not explain all of it.
+func explanation(d Disagreement, divergences []Divergence) string {
+	explainedBy, unexplained := d.Explain(divergences)
+	var names []string
+	for _, div := range explainedBy {
+		names = append(names, div.Name)
+	}
+	if len(unexplained) != 0 {
+		names = append(names, "UNEXPLAINED")
+	}
+	return strings.Join(names, ", ")
+}
+
+func quote(ss []string) []string {
+	out := make([]string, len(ss))
+	for i, s := range ss {
+		out[i] = fmt.Sprintf("%q", s)
+	}
+	return out
+}
+
+// countingWriter counts the bytes written to w, and records the first error.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
@@ -0,0 +1,257 @@
+package difftest
+
+import (
+	"bytes"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// divergences are the known differences between the implementations, each
+// of which is expected to account for at least one disagreement over the
+// generated corpus. Each explains a result relative to the reference result,
+// which is semv's wherever semv accepts the versions involved.
+var divergences = []Divergence{
+	{
+		Name:   "v prefix",
+		Reason: `semv.Parse rejects a leading "v", which x/mod/semver requires and Masterminds allows`,
+		Explains: func(d Disagreement, r Result) bool {
+			return d.Kind == Acceptance && hasVPrefix(d.Inputs[0]) && r.Value == "true" &&
+				(r.Implementation == "x/mod" || r.Implementation == "masterminds")
+		},
+	},
+	{
+		Name:   "partial versions",
+		Reason: `semv.Parse accepts "1" and "1.2", as do Masterminds, and x/mod/semver if there is no prerelease or metadata; ParseExactSemver2 does not`,
+		Explains: func(d Disagreement, r Result) bool {
+			v := d.Inputs[0]
+			return d.Kind == Acceptance && componentCount(v) < 3 && r.Value == "false" &&
+				(r.Implementation == "semv-exact" || r.Implementation == "x/mod" && strings.ContainsAny(v, "-+"))
+		},
+	},
+	{
+		Name:   "leading zeros",
+		Reason: "semv.Parse and Masterminds accept leading zeros in the major, minor and patch components, which semver 2.0.0 forbids",
+		Explains: func(d Disagreement, r Result) bool {
+			return d.Kind == Acceptance && hasLeadingZero(d.Inputs[0]) && r.Value == "false" &&
+				(r.Implementation == "semv-exact" || r.Implementation == "x/mod")
+		},
+	},
+	{
+		Name:   "large components",
+		Reason: "Masterminds stores components as uint64, and rejects larger ones, which semv and x/mod/semver accept",
+		Explains: func(d Disagreement, r Result) bool {
+			return d.Kind == Acceptance && hasLargeComponent(d.Inputs[0]) && r.Implementation == "masterminds" && r.Value == "false"
+		},
+	},
+	{
+		Name:   "identifiers",
+		Reason: "semv does not validate prerelease and metadata identifiers, so accepts empty identifiers and numeric prerelease identifiers with leading zeros",
+		Explains: func(d Disagreement, r Result) bool {
+			return d.Kind == Acceptance && hasInvalidIdentifier(d.Inputs[0]) && r.Value == "false" &&
+				(r.Implementation == "x/mod" || r.Implementation == "masterminds")
+		},
+	},
+	{
+		Name:   "caret on 0.x",
+		Reason: `semv treats "^0.y.z" as ">=0.y.z <1.0.0", where Masterminds, like npm, treats it as "<0.(y+1).0", or "<0.0.(z+1)" if y is 0`,
+		Explains: func(d Disagreement, r Result) bool {
+			return d.Kind == Satisfaction && strings.Contains(d.Inputs[0], "^0.") &&
+				r.Implementation == "masterminds" && r.Value == "false"
+		},
+	},
+	{
+		Name:   "tilde on 0.0.0",
+		Reason: `Masterminds treats "~0.0.0" as matching any version`,
+		Explains: func(d Disagreement, r Result) bool {
+			return d.Kind == Satisfaction && strings.Contains(d.Inputs[0], "~0.0.0") &&
+				r.Implementation == "masterminds" && r.Value == "true"
+		},
+	},
+	{
+		Name:   "inclusive bounds and prereleases",
+		Reason: `semv's inclusive bounds, i.e. ">=", "<=" and hyphen ranges, admit prereleases of any version within them, which Masterminds excludes unless the range mentions a prerelease`,
+		Explains: func(d Disagreement, r Result) bool {
+			return d.Kind == Satisfaction && isPrerelease(d.Inputs[1]) && hasInclusiveBound(d.Inputs[0]) &&
+				r.Implementation == "masterminds" && r.Value == "false"
+		},
+	},
+	{
+		Name:   "prerelease opt-in",
+		Reason: "Masterminds admits prereleases of any version when a comparator in the range has a prerelease, where semv only admits prereleases of the same major, minor and patch",
+		Explains: func(d Disagreement, r Result) bool {
+			comparators := prereleaseComparators(d.Inputs[0])
+			return d.Kind == Satisfaction && isPrerelease(d.Inputs[1]) && len(comparators) != 0 &&
+				!slices.ContainsFunc(comparators, sameCore(d.Inputs[1])) &&
+				r.Implementation == "masterminds" && r.Value == "true"
+		},
+	},
+	{
+		Name:   "exclusive upper bounds and prereleases",
+		Reason: `semv's "<" bounds exclude prereleases unless the bound is a prerelease of a higher major, minor and patch, so neither "<1.0.0-beta" nor ">=1.0.0-0 <2.0.0" is satisfied by "1.0.0-alpha"; Masterminds admits both, since the range has a prerelease`,
+		Explains: func(d Disagreement, r Result) bool {
+			return d.Kind == Satisfaction && isPrerelease(d.Inputs[1]) && hasExclusiveUpperBound(d.Inputs[0]) &&
+				len(prereleaseComparators(d.Inputs[0])) != 0 && r.Implementation == "masterminds" && r.Value == "true"
+		},
+	},
+}
+
+func hasVPrefix(v string) bool {
+	return strings.HasPrefix(v, "v")
+}
+
+// core returns the major, minor and patch components of v, without any "v"
+// prefix, prerelease or metadata.
+func core(v string) []string {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i != -1 {
+		v = v[:i]
+	}
+	return strings.Split(v, ".")
+}
+
+func componentCount(v string) int {
+	return len(core(v))
+}
+
+func hasLeadingZero(v string) bool {
+	for _, c := range core(v) {
+		if len(c) > 1 && c[0] == '0' {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLargeComponent(v string) bool {
+	for _, c := range core(v) {
+		if _, err := strconv.ParseUint(c, 10, 64); err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func isPrerelease(v string) bool {
+	v, _, _ = strings.Cut(v, "+")
+	return strings.Contains(v, "-")
+}
+
+func hasInvalidIdentifier(v string) bool {
+	v, meta, hasMeta := strings.Cut(v, "+")
+	if hasMeta && strings.Contains("."+meta+".", "..") {
+		return true
+	}
+	_, pre, hasPre := strings.Cut(v, "-")
+	if !hasPre {
+		return false
+	}
+	for _, id := range strings.Split(pre, ".") {
+		if id == "" || len(id) > 1 && id[0] == '0' && strings.Trim(id, "0123456789") == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasInclusiveBound(rng string) bool {
+	return strings.Contains(rng, ">=") || strings.Contains(rng, "<=") || strings.Contains(rng, " - ")
+}
+
+func hasExclusiveUpperBound(rng string) bool {
+	return slices.ContainsFunc(strings.Fields(rng), func(f string) bool {
+		return strings.HasPrefix(f, "<") && !strings.HasPrefix(f, "<=")
+	})
+}
+
+// prereleaseComparators returns the comparators in rng which have a
+// prerelease, including the bounds of hyphen ranges.
+func prereleaseComparators(rng string) []string {
+	var out []string
+	for _, f := range strings.Fields(rng) {
+		if f != "-" && f != "||" && isPrerelease(f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// sameCore returns a func which returns true if a comparator has the same
+// major, minor and patch as v.
+func sameCore(v string) func(comparator string) bool {
+	return func(comparator string) bool {
+		return slices.Equal(core(strings.TrimLeft(comparator, "=<>~^")), core(v))
+	}
+}
+
+func TestRun(t *testing.T) {
+	report := Run(Generate(1, 300), Semv, SemvExact, XMod, Masterminds)
+	report.Divergences = divergences
+	for _, d := range report.Unexplained(divergences) {
+		t.Errorf("unexplained %s disagreement over %q: %v", d.Kind, d.Inputs, d.Results)
+	}
+	used := map[*Divergence]int{}
+	for _, d := range report.Disagreements {
+		explainedBy, _ := d.Explain(divergences)
+		for _, div := range explainedBy {
+			used[div]++
+		}
+	}
+	for i := range divergences {
+		if used[&divergences[i]] == 0 {
+			t.Errorf("divergence %q explains no disagreements; remove it if it no longer applies", divergences[i].Name)
+		}
+	}
+	if testing.Verbose() {
+		var buf bytes.Buffer
+		report.WriteTo(&buf)
+		t.Log("\n" + buf.String())
+	}
+}
+
+func TestRun_Agreement(t *testing.T) {
+	c := Corpus{
+		Versions: []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "2.0.0", "2.1.0", "2.1.1"},
+		Ranges:   []string{"1.0.0", ">=1.0.0 <2.1.0", "^2.0.0", "~2.1.0", "<1.0.0 || >2.1.0"},
+	}
+	report := Run(c, Semv, SemvExact, XMod, Masterminds)
+	for _, d := range report.Disagreements {
+		t.Errorf("unexpected %s disagreement over %q: %v", d.Kind, d.Inputs, d.Results)
+	}
+	n := len(c.Versions)
+	if actual, expected := report.Checked[Ordering], n*(n-1)/2; actual != expected {
+		t.Errorf("got %d ordering checks; want %d", actual, expected)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	a, b := Generate(7, 50), Generate(7, 50)
+	if len(a.Versions) != 50 || len(a.Ranges) != 50 {
+		t.Fatalf("got %d versions and %d ranges; want 50 of each", len(a.Versions), len(a.Ranges))
+	}
+	for i := range a.Versions {
+		if a.Versions[i] != b.Versions[i] || a.Ranges[i] != b.Ranges[i] {
+			t.Fatalf("corpora generated from the same seed differ at %d", i)
+		}
+	}
+}
+
+func TestReport_WriteTo(t *testing.T) {
+	report := Run(Corpus{Versions: []string{"v1.2.3", "1.2.3"}}, Semv, XMod)
+	report.Divergences = divergences
+	var buf bytes.Buffer
+	if _, err := report.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `acceptance: 2 checks, 1 disagreements
+ordering: 0 checks, 0 disagreements
+satisfaction: 0 checks, 0 disagreements
+
+KIND        INPUTS    SEMV   X/MOD  EXPLAINED BY
+acceptance  "v1.2.3"  false  true   v prefix
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", actual, expected)
+	}
+}
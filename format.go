@@ -0,0 +1,216 @@
+package semv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// FormatError is returned by ValidateFormat when a format string using the
+// format language described at Version.Format is malformed.
+type FormatError struct {
+	Format  string
+	Pos     int
+	Problem string
+}
+
+func (err FormatError) Error() string {
+	return fmt.Sprintf("invalid format %q at position %d: %s", err.Format, err.Pos, err.Problem)
+}
+
+// MaxFormatWidth is the greatest width a verb of the format language may be
+// padded to, e.g. "%64p", so that formats from untrusted sources cannot cause
+// huge allocations.
+const MaxFormatWidth = 64
+
+// isFormatLanguage returns true if format should be interpreted using the
+// format language, rather than the original constants such as MajorMinor.
+func isFormatLanguage(format string) bool {
+	return strings.IndexByte(format, '%') != -1
+}
+
+// ValidateFormat returns a FormatError if format uses the format language
+// described at Version.Format, and is malformed. Formats which do not use the
+// format language are always valid.
+func ValidateFormat(format string) error {
+	if !isFormatLanguage(format) {
+		return nil
+	}
+	_, err := Version{}.appendFormat(nil, format)
+	return err
+}
+
+// appendFormat appends v formatted using the format language to dst.
+func (v Version) appendFormat(dst []byte, format string) ([]byte, error) {
+	dst, _, _, err := v.appendSection(dst, format, 0, false)
+	return dst, err
+}
+
+// appendSection appends the section of format starting at i to dst, stopping
+// after the closing brace if nested is true. It returns the index following
+// the section, and whether all of the verbs in the section (excluding any
+// nested sections) were non-zero.
+func (v Version) appendSection(dst []byte, format string, i int, nested bool) ([]byte, int, bool, error) {
+	start, ok := i, true
+	for i < len(format) {
+		switch c := format[i]; c {
+		case '\\':
+			if i+1 == len(format) {
+				return dst, i, false, FormatError{format, i, "trailing backslash"}
+			}
+			dst, i = append(dst, format[i+1]), i+2
+		case '{':
+			sectionStart := len(dst)
+			var sectionOK bool
+			var err error
+			dst, i, sectionOK, err = v.appendSection(dst, format, i+1, true)
+			if err != nil {
+				return dst, i, false, err
+			}
+			if !sectionOK {
+				dst = dst[:sectionStart]
+			}
+		case '}':
+			if !nested {
+				return dst, i, false, FormatError{format, i, "unexpected '}'"}
+			}
+			return dst, i + 1, ok, nil
+		case '%':
+			var nonZero bool
+			var err error
+			dst, i, nonZero, err = v.appendVerb(dst, format, i)
+			if err != nil {
+				return dst, i, false, err
+			}
+			ok = ok && nonZero
+		default:
+			dst, i = append(dst, c), i+1
+		}
+	}
+	if nested {
+		return dst, i, false, FormatError{format, start - 1, "unclosed '{'"}
+	}
+	return dst, i, ok, nil
+}
+
+// appendVerb appends the value of the verb starting at format[i], which is
+// '%', to dst. It returns the index following the verb, and whether the value
+// was non-zero.
+func (v Version) appendVerb(dst []byte, format string, i int) ([]byte, int, bool, error) {
+	start := i
+	i++
+	if i < len(format) && format[i] == '%' {
+		return append(dst, '%'), i + 1, true, nil
+	}
+	zero := i < len(format) && format[i] == '0'
+	if zero {
+		i++
+	}
+	width := 0
+	for ; i < len(format) && '0' <= format[i] && format[i] <= '9'; i++ {
+		if width = width*10 + int(format[i]-'0'); width > MaxFormatWidth {
+			return dst, i, false, FormatError{format, start, fmt.Sprintf("width exceeds %d", MaxFormatWidth)}
+		}
+	}
+	index := 0
+	if i < len(format) && format[i] == '[' {
+		end := strings.IndexByte(format[i:], ']')
+		if end == -1 {
+			return dst, i, false, FormatError{format, i, "unclosed '['"}
+		}
+		n, err := strconv.Atoi(format[i+1 : i+end])
+		if err != nil || n < 1 {
+			return dst, i, false, FormatError{format, i, "identifier index must be a positive integer"}
+		}
+		index, i = n, i+end+1
+	}
+	if i == len(format) {
+		return dst, i, false, FormatError{format, start, "missing verb"}
+	}
+	verb := format[i]
+	i++
+	var s string
+	switch verb {
+	default:
+		return dst, i, false, FormatError{format, i - 1, fmt.Sprintf("unknown verb %q", verb)}
+	case 'M', 'm', 'p':
+		if index != 0 {
+			return dst, i, false, FormatError{format, start, "only %r and %b take an identifier index"}
+		}
+		n, b := v.Major, v.bigMajor
+		if verb == 'm' {
+			n, b = v.Minor, v.bigMinor
+		} else if verb == 'p' {
+			n, b = v.Patch, v.bigPatch
+		}
+		if isBig(n, b) {
+			return appendPadded(dst, b, width, zero), i, true, nil
+		}
+		var buf [20]byte
+		digits := strconv.AppendInt(buf[:0], int64(n), 10)
+		return appendPadded(dst, digits, width, zero), i, n != 0, nil
+	case 'r':
+		s = v.Pre
+	case 'b':
+		s = v.Meta
+	}
+	if index != 0 {
+		s = identifier(s, index)
+	}
+	return appendPadded(dst, s, width, zero), i, s != "", nil
+}
+
+// appendPadded appends s to dst, left-padded with zeros or spaces to width.
+func appendPadded[T string | []byte](dst []byte, s T, width int, zero bool) []byte {
+	pad := byte(' ')
+	if zero {
+		pad = '0'
+	}
+	for n := len(s); n < width; n++ {
+		dst = append(dst, pad)
+	}
+	return append(dst, s...)
+}
+
+// identifier returns the nth (1-based) dot-separated identifier in s, or the
+// empty string if there are fewer than n.
+func identifier(s string, n int) string {
+	for ; n > 1; n-- {
+		dot := strings.IndexByte(s, '.')
+		if dot == -1 {
+			return ""
+		}
+		s = s[dot+1:]
+	}
+	if dot := strings.IndexByte(s, '.'); dot != -1 {
+		return s[:dot]
+	}
+	return s
+}
+
+// FuncMap returns functions for formatting versions in text/template and
+// html/template templates:
+//
+//     semver     parses a string using Parse
+//     format     formats a version, e.g. {{.Version | format "%M.%m"}}
+//     major, minor, patch, pre, meta
+//                return the respective field of a version
+//     preIdent   returns the nth (1-based) prerelease identifier of a version
+//     incMajor, incMinor, incPatch
+//                return a version with the respective field incremented
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"semver":   Parse,
+		"format":   func(format string, v Version) string { return v.Format(format) },
+		"major":    Version.MajorString,
+		"minor":    Version.MinorString,
+		"patch":    Version.PatchString,
+		"pre":      func(v Version) string { return v.Pre },
+		"meta":     func(v Version) string { return v.Meta },
+		"preIdent": func(n int, v Version) string { return identifier(v.Pre, n) },
+		"incMajor": Version.IncrementMajor,
+		"incMinor": Version.IncrementMinor,
+		"incPatch": Version.IncrementPatch,
+	}
+}
@@ -0,0 +1,100 @@
+package semv
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+var formatTests = []struct {
+	version, format, expected string
+}{
+	// Original format constants.
+	{"1.2.3-beta.1+build.5", MajorMinor, "1.2"},
+	{"1.2.3-beta.1+build.5", MMPPre, "1.2.3-beta.1"},
+	{"1.2.3-beta.1+build.5", Complete, "1.2.3-beta.1+build.5"},
+	{"1.2.3-beta.1+build.5", "", "1.2.3-beta.1+build.5"},
+	{"1.2.3-beta.1+build.5", "M.m.p" + PreRaw, "1.2.3beta.1"},
+	// Format language.
+	{"1.2.3", "mod-%M.%m.%p", "mod-1.2.3"},
+	{"1.2.3", "v%M.%m.%03p", "v1.2.003"},
+	{"1.2.1234", "%03p", "1234"},
+	{"1.2.3", "[%4M]", "[   1]"},
+	{"1.2.0", "%M.%m{.%p}", "1.2"},
+	{"1.2.3", "%M.%m{.%p}", "1.2.3"},
+	{"1.2.3", "%M.%m.%p{-%r}{+%b}", "1.2.3"},
+	{"1.2.3-rc.2+linux.amd64", "%M.%m.%p{-%r}{+%b}", "1.2.3-rc.2+linux.amd64"},
+	{"1.2.3-rc.2+linux.amd64", "%[1]r %[2]r %[2]b", "rc 2 amd64"},
+	{"1.2.3-rc.2", "%M.%m.%p{ (candidate %[2]r{, build %[3]r})}", "1.2.3 (candidate 2)"},
+	{"1.2.3-rc", "%M.%m.%p{ (candidate %[2]r)}", "1.2.3"},
+	{"1.2.3", `100%% \{%M\} \\ \%`, `100% {1} \ %`},
+	{"0.0.0", "{%M}{%m}{%p}x", "x"},
+	{"1.2.3", "%M.%m.%p}", `%!(invalid format "%M.%m.%p}" at position 8: unexpected '}')`},
+	{"1.2.3", "%M{.%m", `%!(invalid format "%M{.%m" at position 2: unclosed '{')`},
+	{"1.2.3", "%M.%q", `%!(invalid format "%M.%q" at position 4: unknown verb 'q')`},
+}
+
+func TestVersion_Format(t *testing.T) {
+	for _, test := range formatTests {
+		v := MustParse(test.version)
+		if actual := v.Format(test.format); actual != test.expected {
+			t.Errorf("got %q.Format(%q) == %q; want %q", test.version, test.format, actual, test.expected)
+		}
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	valid := []string{"", MajorMinor, Complete, "M.m.p}", "%M.%m{.%p}", `\%%M`, "%[12]r", "%064p"}
+	for _, f := range valid {
+		if err := ValidateFormat(f); err != nil {
+			t.Errorf("got error %q validating %q; want nil", err, f)
+		}
+	}
+	invalid := map[string]string{
+		"%M}":    "unexpected '}'",
+		"{{%M}":  "unclosed '{'",
+		"%M\\":   "trailing backslash",
+		"%":      "missing verb",
+		"%03":    "missing verb",
+		"%x":     "unknown verb 'x'",
+		"%[0]r":  "identifier index must be a positive integer",
+		"%[1r":   "unclosed '['",
+		"%[1]M":  "only %r and %b take an identifier index",
+		"{%M}%z": "unknown verb 'z'",
+		"%65p":   "width exceeds 64",
+		"%0100r": "width exceeds 64",
+	}
+	for f, expected := range invalid {
+		err := ValidateFormat(f)
+		if err == nil {
+			t.Errorf("got nil error validating %q; want %q", f, expected)
+			continue
+		}
+		if _, ok := err.(FormatError); !ok {
+			t.Errorf("got %T validating %q; want FormatError", err, f)
+		}
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %q validating %q; want %q", err, f, expected)
+		}
+	}
+}
+
+func TestVersion_Format_WidthLimit(t *testing.T) {
+	expected := `%!(invalid format "%99999999p" at position 0: width exceeds 64)`
+	if actual := MustParse("1.2.3").Format("%99999999p"); actual != expected {
+		t.Errorf("got %q; want %q", actual, expected)
+	}
+}
+
+func TestFuncMap(t *testing.T) {
+	tmpl := template.Must(template.New("").Funcs(FuncMap()).Parse(
+		`{{format "v%M.%m" .}} {{major .}}.{{minor .}}.{{patch .}} {{preIdent 1 .}} {{meta .}} ` +
+			`{{incMinor . | format "%M.%m.%p"}} {{(semver "2.0.0").IncrementPatch}}`))
+	var b strings.Builder
+	if err := tmpl.Execute(&b, MustParse("1.2.3-beta.1+abc")); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := b.String(), "v1.2 1.2.3 beta abc 1.3.0 2.0.1"; actual != expected {
+		t.Errorf("got %q; want %q", actual, expected)
+	}
+}
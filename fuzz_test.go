@@ -0,0 +1,92 @@
+package semv
+
+import "testing"
+
+var fuzzVersionSeeds = []string{
+	"0.0.0", "1", "1.2", "1.2.3", "01.02.03", "1.2.3-beta", "1.2.3-beta.1+build.5",
+	"1.2.3-0.a.01", "1.2.3-x-y-z.--", "1.2.3+meta-data", "1.2-rc.1", "1-1",
+	"1.0.99999999999999999999", "1.2.3-1.x", "1.2.3--x", "1.2.3-a.b.c.d.e",
+	"", ".", "1..2", "1.2.3.4", "1.2.x", "v1.2.3", "1.2.3-é", "1.2.3++",
+}
+
+var fuzzRangeSeeds = []string{
+	"*", "1.2.3", "=1.2.3", ">1.2.3", ">=1.2.3", "<1.2.3", "<=1.2.3", "~1.2.3",
+	"^1.2.3", "^0.1.2", "~1.2", "^1", ">=1.2.3 <2.0.0", "1.2.3 - 2.3.4",
+	">=1.0.0-beta <1.0.0", "^1.2.3-rc.1", "> 1.2.3", "<0.0.0", "x", "",
+}
+
+// FuzzParse checks that any version which parses can be printed and parsed
+// again without change, and that the parse funcs agree with each other.
+func FuzzParse(f *testing.F) {
+	for _, s := range fuzzVersionSeeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := Parse(s)
+		b, bErr := ParseBytes([]byte(s))
+		if v != b || (err == nil) != (bErr == nil) {
+			t.Fatalf("Parse(%q) == %#v, %v; ParseBytes gave %#v, %v", s, v, err, b, bErr)
+		}
+		exact, exactErr := ParseExactSemver2(s)
+		if exactErr == nil && (err != nil || exact != v) {
+			t.Fatalf("ParseExactSemver2(%q) succeeded, but Parse gave %#v, %v", s, v, err)
+		}
+		if err != nil {
+			return
+		}
+		again, err := Parse(v.String())
+		if err != nil {
+			t.Fatalf("Parse(%q).String() == %q, which does not parse: %s", s, v, err)
+		}
+		if again != v {
+			t.Fatalf("Parse(%q) == %#v, but parsing its String() %q gave %#v", s, v, v.String(), again)
+		}
+		if canonical, err := ParseExactSemver2(v.Canonical()); err != nil || !canonical.Equals(v) {
+			t.Fatalf("Canonical() of %q is %q, which gave %#v, %v", s, v.Canonical(), canonical, err)
+		}
+		if lossless, err := ParseLossless(s); err != nil || lossless.String() != s || !lossless.Equals(v) {
+			t.Fatalf("ParseLossless(%q) == %q, %v", s, lossless, err)
+		}
+	})
+}
+
+// FuzzCompare checks that Less is a strict weak order over any three
+// versions, and that Equals agrees with it.
+func FuzzCompare(f *testing.F) {
+	for i, s := range fuzzVersionSeeds {
+		f.Add(s, fuzzVersionSeeds[(i+1)%len(fuzzVersionSeeds)], fuzzVersionSeeds[(i+7)%len(fuzzVersionSeeds)])
+	}
+	f.Fuzz(func(t *testing.T, a, b, c string) {
+		va, errA := Parse(a)
+		vb, errB := Parse(b)
+		vc, errC := Parse(c)
+		if errA != nil || errB != nil || errC != nil {
+			return
+		}
+		checkOrder(t, va, vb, vc)
+	})
+}
+
+// FuzzParseRange checks that any range which parses can be printed and parsed
+// again without change.
+func FuzzParseRange(f *testing.F) {
+	for i, s := range fuzzRangeSeeds {
+		f.Add(s, fuzzVersionSeeds[i%len(fuzzVersionSeeds)])
+	}
+	f.Fuzz(func(t *testing.T, s, version string) {
+		r, err := ParseRange(s)
+		if err != nil {
+			return
+		}
+		again, err := ParseRange(r.String())
+		if err != nil {
+			t.Fatalf("ParseRange(%q).String() == %q, which does not parse: %s", s, r, err)
+		}
+		if !again.Equals(r) {
+			t.Fatalf("ParseRange(%q) == %q, which parses as %q", s, r, again)
+		}
+		if v, err := Parse(version); err == nil && r.SatisfiedBy(v) != again.SatisfiedBy(v) {
+			t.Fatalf("%q and %q disagree on whether %q satisfies them", r, again, v)
+		}
+	})
+}
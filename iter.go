@@ -0,0 +1,132 @@
+package semv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"sort"
+	"strings"
+)
+
+// LineError is reported by ParseSeq for each line which fails to parse.
+type LineError struct {
+	// Line is the 1-based line number of the input.
+	Line int
+	// Input is the text of the line, with surrounding whitespace removed.
+	Input string
+	// Err is the error returned by the parser.
+	Err error
+}
+
+func (err LineError) Error() string {
+	return fmt.Sprintf("line %d: parsing %q: %s", err.Line, err.Input, err.Err)
+}
+
+// Unwrap returns the underlying parse error.
+func (err LineError) Unwrap() error { return err.Err }
+
+// All returns an iterator over the versions in this list, in their original
+// order, yielding the index of each version along with it.
+func (vl VersionList) All() iter.Seq2[int, Version] {
+	return func(yield func(int, Version) bool) {
+		for i, v := range vl {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+// Ascending returns an iterator over the versions in this list, from lowest
+// to highest. The list itself is not modified.
+func (vl VersionList) Ascending() iter.Seq[Version] {
+	return func(yield func(Version) bool) {
+		for _, v := range vl.Sorted() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Descending returns an iterator over the versions in this list, from highest
+// to lowest. The list itself is not modified.
+func (vl VersionList) Descending() iter.Seq[Version] {
+	return func(yield func(Version) bool) {
+		for _, v := range vl.SortedDesc() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Satisfying returns an iterator over the versions in this list which
+// satisfy the passed Range, in their original order.
+func (vl VersionList) Satisfying(r Range) iter.Seq[Version] {
+	return func(yield func(Version) bool) {
+		for _, v := range vl {
+			if r.SatisfiedBy(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ParseSeq returns an iterator which lazily reads r line by line, parsing
+// each line using Parse. Surrounding whitespace is ignored, and blank lines
+// are skipped. Lines which fail to parse are yielded with a LineError,
+// and iteration continues with the next line. If reading from r fails, the
+// read error is yielded and iteration stops.
+func ParseSeq(r io.Reader) iter.Seq2[Version, error] {
+	return func(yield func(Version, error) bool) {
+		scanner := bufio.NewScanner(r)
+		line := 0
+		for scanner.Scan() {
+			line++
+			s := strings.TrimSpace(scanner.Text())
+			if s == "" {
+				continue
+			}
+			v, err := Parse(s)
+			if err != nil {
+				err = LineError{Line: line, Input: s, Err: err}
+			}
+			if !yield(v, err) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(Version{}, err)
+		}
+	}
+}
+
+// SortBy sorts s in place from lowest to highest version, where version
+// returns the Version of each element. The sort is stable, so elements with
+// equal versions keep their original order. E.g. to sort a ReleaseList:
+//
+//	SortBy(releases, func(r Release) Version { return r.Version })
+func SortBy[T any](s []T, version func(T) Version) {
+	sort.SliceStable(s, func(i, j int) bool {
+		return version(s[i]).Less(version(s[j]))
+	})
+}
+
+// MaxBy returns the element of s with the highest version, where version
+// returns the Version of each element. Of elements with equal versions, the
+// first is returned. If s is empty, the second return value is false.
+func MaxBy[T any](s []T, version func(T) Version) (T, bool) {
+	var max T
+	if len(s) == 0 {
+		return max, false
+	}
+	max = s[0]
+	for _, e := range s[1:] {
+		if version(max).Less(version(e)) {
+			max = e
+		}
+	}
+	return max, true
+}
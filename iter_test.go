@@ -0,0 +1,134 @@
+package semv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestVersionList_All(t *testing.T) {
+	vl := MustParseList("1.0.0", "0.1.0", "2.0.0")
+	var actual []string
+	for i, v := range vl.All() {
+		actual = append(actual, fmt.Sprintf("%d:%s", i, v))
+	}
+	if s, expected := fmt.Sprint(actual), "[0:1.0.0 1:0.1.0 2:2.0.0]"; s != expected {
+		t.Errorf("got %s; want %s", s, expected)
+	}
+}
+
+func TestVersionList_Ascending(t *testing.T) {
+	vl := newRandomisedVersionList()
+	original := vl.Clone()
+	var actual VersionList
+	for v := range vl.Ascending() {
+		actual = append(actual, v)
+	}
+	expected := newOrderedVersionList()
+	if len(actual) != len(expected) {
+		t.Fatalf("got %d versions; want %d", len(actual), len(expected))
+	}
+	for i := range expected {
+		if !actual[i].Equals(expected[i]) {
+			t.Fatalf("got %s at index %d; want %s", actual[i], i, expected[i])
+		}
+	}
+	for i := range original {
+		if vl[i] != original[i] {
+			t.Fatalf("Ascending modified the list at index %d", i)
+		}
+	}
+}
+
+func TestVersionList_Descending(t *testing.T) {
+	vl := newRandomisedVersionList()
+	var actual VersionList
+	for v := range vl.Descending() {
+		actual = append(actual, v)
+		if len(actual) == 3 {
+			break
+		}
+	}
+	assertVersionList(t, "Descending", actual, "5.8.0", "3.5.6", "3.0.0")
+}
+
+func TestVersionList_Satisfying(t *testing.T) {
+	vl := MustParseList("1.2.0", "2.0.0", "1.0.0", "1.5.0-beta", "1.9.9")
+	var actual VersionList
+	for v := range vl.Satisfying(MustParseRange("^1.0.0")) {
+		actual = append(actual, v)
+	}
+	assertVersionList(t, "Satisfying", actual, "1.2.0", "1.0.0", "1.9.9")
+}
+
+func TestParseSeq(t *testing.T) {
+	input := "1.0.0\n  latest \n\n2.1\nv1.2-foo_bar\n3.0.0-rc.1\n"
+	var parsed VersionList
+	var errs []string
+	for v, err := range ParseSeq(strings.NewReader(input)) {
+		if err != nil {
+			var lineErr LineError
+			if !errors.As(err, &lineErr) {
+				t.Fatalf("got error of type %T; want LineError", err)
+			}
+			errs = append(errs, fmt.Sprintf("%d:%s", lineErr.Line, lineErr.Input))
+			continue
+		}
+		parsed = append(parsed, v)
+	}
+	assertVersionList(t, "ParseSeq", parsed, "1.0.0", "2.1", "3.0.0-rc.1")
+	if s, expected := fmt.Sprint(errs), "[2:latest 5:v1.2-foo_bar]"; s != expected {
+		t.Errorf("got errors %s; want %s", s, expected)
+	}
+}
+
+func TestParseSeq_ReadError(t *testing.T) {
+	readErr := errors.New("disk on fire")
+	r := io.MultiReader(strings.NewReader("1.0.0\n"), iotest.ErrReader(readErr))
+	var results []string
+	for v, err := range ParseSeq(r) {
+		if err != nil {
+			results = append(results, err.Error())
+			continue
+		}
+		results = append(results, v.String())
+	}
+	if s, expected := fmt.Sprint(results), "[1.0.0 disk on fire]"; s != expected {
+		t.Errorf("got %s; want %s", s, expected)
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	releases := ReleaseList{
+		{Version: MustParse("2.0.0")},
+		{Version: MustParse("1.0.0+b"), Status: Status{Deprecated: "old"}},
+		{Version: MustParse("1.0.0-beta")},
+		{Version: MustParse("1.0.0+a")},
+	}
+	SortBy(releases, func(r Release) Version { return r.Version })
+	expected := []string{"1.0.0-beta", "1.0.0+b", "1.0.0+a", "2.0.0"}
+	for i, r := range releases {
+		if r.Version.String() != expected[i] {
+			t.Errorf("got %s at index %d; want %s", r.Version, i, expected[i])
+		}
+	}
+}
+
+func TestMaxBy(t *testing.T) {
+	releases := ReleaseList{
+		{Version: MustParse("1.0.0")},
+		{Version: MustParse("1.2.0+first")},
+		{Version: MustParse("1.2.0+second")},
+		{Version: MustParse("1.2.0-rc.1")},
+	}
+	max, ok := MaxBy(releases, func(r Release) Version { return r.Version })
+	if !ok || max.Version.String() != "1.2.0+first" {
+		t.Errorf("got %s, %t; want 1.2.0+first, true", max.Version, ok)
+	}
+	if _, ok := MaxBy(ReleaseList{}, func(r Release) Version { return r.Version }); ok {
+		t.Errorf("got true for empty list; want false")
+	}
+}
@@ -0,0 +1,262 @@
+/*
+Package lint flags risky or malformed version constraints in dependency
+declarations, such as ranges with no upper bound, ranges which no version can
+satisfy, and carets on 0.x versions, which do not mean what npm users expect.
+
+Each Finding identifies the Rule it breaks, has a Severity, and where
+possible suggests a replacement constraint which fixes it.
+*/
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samsalisbury/semv"
+)
+
+type (
+	// Rule identifies the kind of problem a Finding describes.
+	Rule string
+	// Severity is how serious a Finding is.
+	Severity int
+	// Finding is a problem with a constraint.
+	Finding struct {
+		// Package is the name of the package the constraint is declared for,
+		// if it was linted using Manifest.
+		Package  string
+		Rule     Rule
+		Severity Severity
+		// Constraint is the constraint exactly as it was written.
+		Constraint string
+		Message    string
+		// Fix is a suggested replacement for the whole constraint, or empty
+		// if there is no obvious fix.
+		Fix string
+	}
+	// Source provides the versions available for each package.
+	// resolver.Catalog implements Source.
+	Source interface {
+		Versions(name string) (semv.VersionList, error)
+	}
+)
+
+const (
+	// Malformed constraints cannot be parsed.
+	Malformed Rule = "malformed"
+	// Unsatisfiable constraints contain a range which no version satisfies,
+	// e.g. ">2.0.0 <1.0.0".
+	Unsatisfiable Rule = "unsatisfiable"
+	// Unbounded constraints contain a range with no upper bound, e.g.
+	// ">=1.0.0", which admits future breaking changes.
+	Unbounded Rule = "unbounded"
+	// Redundant constraints contain a comparator which does not change the
+	// range it is part of, e.g. the "<2.0.0" in "^1.2.0 <2.0.0".
+	Redundant Rule = "redundant"
+	// CaretZero constraints contain a caret on a 0.x version. Here, "^0.2.3"
+	// means ">=0.2.3 <1.0.0", but in npm it means ">=0.2.3 <0.3.0".
+	CaretZero Rule = "caret-zero"
+	// PrereleasePin constraints are satisfied only by a single prerelease,
+	// so they never pick up fixes, nor the release itself.
+	PrereleasePin Rule = "prerelease-pin"
+	// Unavailable constraints are not satisfied by any known version.
+	Unavailable Rule = "unavailable"
+)
+
+const (
+	// Info findings are matters of style.
+	Info Severity = iota
+	// Warning findings are likely to cause surprising resolutions.
+	Warning
+	// Error findings prevent any version being resolved.
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// String returns the finding in the form
+// "package: severity rule: message (fix: constraint)", omitting the package
+// and fix if they are empty.
+func (f Finding) String() string {
+	s := fmt.Sprintf("%s %s: %s", f.Severity, f.Rule, f.Message)
+	if f.Package != "" {
+		s = f.Package + ": " + s
+	}
+	if f.Fix != "" {
+		s += " (fix: " + f.Fix + ")"
+	}
+	return s
+}
+
+// Lint returns the problems with constraint, which is a range set such as
+// "^1.2.0 || ^2.0.0". If known is not nil, it is the list of versions which
+// exist, and constraints which none of them satisfy are flagged as
+// Unavailable.
+func Lint(constraint string, known semv.VersionList) []Finding {
+	rs, err := semv.ParseRangeSet(constraint)
+	if err != nil {
+		return []Finding{{Rule: Malformed, Severity: Error, Constraint: constraint, Message: err.Error()}}
+	}
+	var findings []Finding
+	add := func(rule Rule, severity Severity, fix string, format string, args ...interface{}) {
+		findings = append(findings, Finding{Rule: rule, Severity: severity, Constraint: constraint,
+			Message: fmt.Sprintf(format, args...), Fix: fix})
+	}
+	parts := strings.Split(constraint, "||")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	// replace returns the constraint with its ith range replaced by s.
+	replace := func(i int, s string) string {
+		fixed := append([]string(nil), parts...)
+		fixed[i] = s
+		return strings.Join(fixed, " || ")
+	}
+	latest, hasLatest := known.Stable().Max()
+	satisfiable := false
+	for i, r := range rs {
+		part := parts[i]
+		if r.IsEmpty() {
+			add(Unsatisfiable, Error, "", "%s is not satisfied by any version", part)
+			continue
+		}
+		satisfiable = true
+		if n := r.Normalize(); n.Max == nil && n.MaxEqual == nil {
+			fix := ""
+			switch {
+			case n.MinEqual != nil:
+				fix = replace(i, suggest(*n.MinEqual))
+			case n.Min != nil:
+				fix = replace(i, fmt.Sprintf(">%s <%s", n.Min, n.Min.IncrementMajor()))
+			case hasLatest:
+				fix = replace(i, suggest(latest))
+			}
+			add(Unbounded, Warning, fix, "%s has no upper bound, so it admits future major versions", part)
+		}
+		comparators := semv.SplitComparators(part)
+		if len(comparators) == 3 && comparators[1] == "-" {
+			comparators = nil
+		}
+		kept, removed := withoutRedundant(comparators, r)
+		for _, c := range removed {
+			add(Redundant, Info, replace(i, strings.Join(kept, " ")), "%s does not change the range %s", c, part)
+		}
+		for _, c := range comparators {
+			if !strings.HasPrefix(c, "^") {
+				continue
+			}
+			// A caret on a bare major version, e.g. "^0", means the same as in
+			// npm.
+			v, err := semv.ParseAny(c)
+			if err != nil || v.Major != 0 || !strings.Contains(c, ".") {
+				continue
+			}
+			fix := "~" + strings.TrimPrefix(c, "^")
+			if v.Minor == 0 {
+				fix = "=" + strings.TrimPrefix(c, "^")
+			}
+			add(CaretZero, Warning, replace(i, strings.Replace(part, c, fix, 1)),
+				"%s allows any version below 1.0.0, whereas in npm it allows only %s", c, fix)
+		}
+		if v, ok := r.Exact(); ok && v.IsPrerelease() {
+			add(PrereleasePin, Warning, replace(i, suggest(v)),
+				"%s is pinned to a prerelease, so it will not pick up later prereleases or the release", part)
+		}
+	}
+	if known != nil && satisfiable && !anySatisfies(rs, known) {
+		fix := ""
+		if hasLatest {
+			fix = suggest(latest)
+		}
+		add(Unavailable, Error, fix, "%s is not satisfied by any of the %d known versions", constraint, len(known))
+	}
+	return findings
+}
+
+// Manifest lints the constraint declared for each package, looking up known
+// versions from src if it is not nil. The findings are ordered by package
+// name.
+func Manifest(constraints map[string]string, src Source) ([]Finding, error) {
+	names := make([]string, 0, len(constraints))
+	for name := range constraints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var findings []Finding
+	for _, name := range names {
+		var known semv.VersionList
+		if src != nil {
+			var err error
+			if known, err = src.Versions(name); err != nil {
+				return nil, err
+			}
+			if known == nil {
+				known = semv.VersionList{}
+			}
+		}
+		for _, f := range Lint(constraints[name], known) {
+			f.Package = name
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+// MaxSeverity returns the highest severity of the findings, and false if
+// there are none.
+func MaxSeverity(findings []Finding) (Severity, bool) {
+	max := Info
+	for _, f := range findings {
+		if f.Severity > max {
+			max = f.Severity
+		}
+	}
+	return max, len(findings) != 0
+}
+
+// suggest returns a range allowing compatible upgrades from v: a caret range,
+// or a tilde range for 0.x versions, whose compatible upgrades are only
+// patches.
+func suggest(v semv.Version) string {
+	if v.Major == 0 {
+		return "~" + v.String()
+	}
+	return "^" + v.String()
+}
+
+// withoutRedundant splits comparators into those kept and those removed,
+// removing each one which, along with those already removed, leaves the
+// range r unchanged.
+func withoutRedundant(comparators []string, r semv.Range) (kept, removed []string) {
+	kept = append([]string(nil), comparators...)
+	for i := 0; i < len(kept) && len(kept) > 1; {
+		rest := append(append([]string(nil), kept[:i]...), kept[i+1:]...)
+		if without, err := semv.ParseRange(strings.Join(rest, " ")); err == nil && without.Equals(r) {
+			removed = append(removed, kept[i])
+			kept = rest
+			continue
+		}
+		i++
+	}
+	return kept, removed
+}
+
+func anySatisfies(rs semv.RangeSet, known semv.VersionList) bool {
+	for _, v := range known {
+		if rs.SatisfiedBy(v) {
+			return true
+		}
+	}
+	return false
+}
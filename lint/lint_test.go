@@ -0,0 +1,109 @@
+package lint
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/samsalisbury/semv"
+)
+
+func TestLint(t *testing.T) {
+	tests := map[string][]string{
+		"^1.2.0":             nil,
+		"~0.2.3":             nil,
+		"1.0.0 - 2.0.0":      nil,
+		"=0.0.3":             nil,
+		"^0":                 nil,
+		"not a range":        {"error malformed"},
+		">2 <1":              {"error unsatisfiable"},
+		">=1.0.0":            {"warning unbounded fix ^1.0.0"},
+		">1.0.0":             {"warning unbounded fix >1.0.0 <2.0.0"},
+		"*":                  {"warning unbounded"},
+		"^1.2.0 <2.0.0":      {"info redundant fix ^1.2.0"},
+		">=1.0.0 >=1.2.0 <2": {"info redundant fix >=1.2.0 <2"},
+		"^0.2.3":             {"warning caret-zero fix ~0.2.3"},
+		"^0.0.3":             {"warning caret-zero fix =0.0.3"},
+		"1.2.3-beta.1":       {"warning prerelease-pin fix ^1.2.3-beta.1"},
+		"0.2.3-beta.1":       {"warning prerelease-pin fix ~0.2.3-beta.1"},
+		"^1.0.0 || >=3.0.0":  {"warning unbounded fix ^1.0.0 || ^3.0.0"},
+		"^1.0.0 || >2 <1":    {"error unsatisfiable"},
+		">=1.0.0 >=1.0.0":    {"warning unbounded fix ^1.0.0", "info redundant fix >=1.0.0"},
+	}
+	for constraint, expected := range tests {
+		actual := summarize(Lint(constraint, nil))
+		if strings.Join(actual, "; ") != strings.Join(expected, "; ") {
+			t.Errorf("Lint(%q): got %q; want %q", constraint, actual, expected)
+		}
+	}
+}
+
+func summarize(findings []Finding) []string {
+	var out []string
+	for _, f := range findings {
+		s := f.Severity.String() + " " + string(f.Rule)
+		if f.Fix != "" {
+			s += " fix " + f.Fix
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func TestLint_Known(t *testing.T) {
+	known := semv.MustParseList("1.0.0", "1.4.0", "2.0.0-rc.1")
+	tests := map[string][]string{
+		"^1.2.0": nil,
+		"^2.0.0": {"error unavailable fix ^1.4.0"},
+		"*":      {"warning unbounded fix ^1.4.0"},
+		">2 <1":  {"error unsatisfiable"},
+	}
+	for constraint, expected := range tests {
+		actual := summarize(Lint(constraint, known))
+		if strings.Join(actual, "; ") != strings.Join(expected, "; ") {
+			t.Errorf("Lint(%q): got %q; want %q", constraint, actual, expected)
+		}
+	}
+	if actual := summarize(Lint("^1.0.0", semv.VersionList{})); len(actual) != 1 || actual[0] != "error unavailable" {
+		t.Errorf("got %q; want only an unavailable error without a fix", actual)
+	}
+}
+
+func TestFinding_String(t *testing.T) {
+	f := Lint("^0.2.3", nil)[0]
+	f.Package = "left-pad"
+	expected := "left-pad: warning caret-zero: ^0.2.3 allows any version below 1.0.0, whereas in npm it allows only ~0.2.3 (fix: ~0.2.3)"
+	if actual := f.String(); actual != expected {
+		t.Errorf("got %q; want %q", actual, expected)
+	}
+}
+
+type source map[string]semv.VersionList
+
+func (s source) Versions(name string) (semv.VersionList, error) {
+	if name == "broken" {
+		return nil, errors.New("registry unavailable")
+	}
+	return s[name], nil
+}
+
+func TestManifest(t *testing.T) {
+	src := source{"a": semv.MustParseList("1.0.0")}
+	findings, err := Manifest(map[string]string{"b": "^1.0.0", "a": ">=1.0.0"}, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual []string
+	for _, f := range findings {
+		actual = append(actual, f.Package+" "+string(f.Rule))
+	}
+	if expected := "a unbounded; b unavailable"; strings.Join(actual, "; ") != expected {
+		t.Errorf("got %q; want %q", actual, expected)
+	}
+	if max, ok := MaxSeverity(findings); !ok || max != Error {
+		t.Errorf("got MaxSeverity == %s, %t; want error, true", max, ok)
+	}
+	if _, err := Manifest(map[string]string{"broken": "^1.0.0"}, src); err == nil {
+		t.Errorf("got nil error; want the error from the source")
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Package lockfile records resolved package versions so that they can be
+reproduced exactly.
+
+A lockfile is a JSON document recording, for each package, the range that was
+requested, the version that was resolved from it, and an integrity hash of the
+resolved package contents. Lockfiles can be checked against updated
+constraints, and minimally updated, so that only packages whose requested
+range no longer matches their locked version are moved.
+*/
+package lockfile
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/samsalisbury/semv"
+)
+
+// FormatVersion is the version of the lockfile format written by this package.
+const FormatVersion = 1
+
+type (
+	// Lockfile records the resolved version of each package.
+	Lockfile struct {
+		FormatVersion int              `json:"lockfileVersion"`
+		Packages      map[string]Entry `json:"packages"`
+	}
+	// Entry is a single locked package.
+	Entry struct {
+		// Range is the requested range, exactly as it was written.
+		Range string `json:"range"`
+		// Version is the version resolved from Range.
+		Version semv.Version `json:"version"`
+		// Integrity is a hash of the package contents, in the form
+		// "sha256-<base64 digest>", as produced by Integrity.
+		Integrity string `json:"integrity,omitempty"`
+	}
+	// Mismatch describes a package whose locked entry does not match the
+	// current constraints.
+	Mismatch struct {
+		Name   string
+		Reason string
+	}
+	// Change describes a package moved by Update. Old is nil for newly
+	// added packages, and New is nil for removed packages.
+	Change struct {
+		Name     string
+		Old, New *semv.Version
+	}
+	// Source provides the versions available for each package.
+	// resolver.Catalog implements Source.
+	Source interface {
+		Versions(name string) (semv.VersionList, error)
+	}
+	// Hasher returns the integrity hash of a specific version of a package.
+	Hasher func(name string, v semv.Version) (string, error)
+	// UnsupportedFormat is an error returned by Read when the lockfile was
+	// written in a format this package does not understand.
+	UnsupportedFormat struct {
+		FormatVersion int
+	}
+)
+
+func (err UnsupportedFormat) Error() string {
+	return fmt.Sprintf("unsupported lockfile version %d", err.FormatVersion)
+}
+
+func (m Mismatch) String() string {
+	return m.Name + ": " + m.Reason
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("%s: added %s", c.Name, c.New)
+	case c.New == nil:
+		return fmt.Sprintf("%s: removed %s", c.Name, c.Old)
+	}
+	return fmt.Sprintf("%s: %s -> %s", c.Name, c.Old, c.New)
+}
+
+// New returns an empty lockfile.
+func New() *Lockfile {
+	return &Lockfile{FormatVersion: FormatVersion, Packages: map[string]Entry{}}
+}
+
+// Integrity returns the integrity hash of content, in the same
+// "sha256-<base64 digest>" form used by npm and subresource integrity.
+func Integrity(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Verify returns true if content matches the integrity hash of this entry.
+func (e Entry) Verify(content []byte) bool {
+	return e.Integrity == Integrity(content)
+}
+
+// Read reads a lockfile from r.
+func Read(r io.Reader) (*Lockfile, error) {
+	l := &Lockfile{}
+	if err := json.NewDecoder(r).Decode(l); err != nil {
+		return nil, err
+	}
+	if l.FormatVersion != FormatVersion {
+		return nil, UnsupportedFormat{l.FormatVersion}
+	}
+	if l.Packages == nil {
+		l.Packages = map[string]Entry{}
+	}
+	return l, nil
+}
+
+// Load reads a lockfile from the file at path.
+func Load(path string) (*Lockfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Write writes the lockfile to w as indented JSON. Packages are written in
+// name order, so the output is stable.
+func (l *Lockfile) Write(w io.Writer) error {
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// Save writes the lockfile to the file at path, replacing any existing file.
+func (l *Lockfile) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := l.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Set records the resolved version of a package.
+func (l *Lockfile) Set(name, rng string, v semv.Version, integrity string) {
+	l.Packages[name] = Entry{Range: rng, Version: v, Integrity: integrity}
+}
+
+// Check compares the lockfile against constraints, which map package names to
+// requested range strings. It returns a Mismatch for each package that is
+// missing from the lockfile, that is locked but no longer requested, or whose
+// locked version does not satisfy its requested range. If the lockfile still
+// satisfies all the constraints, Check returns no mismatches.
+func (l *Lockfile) Check(constraints map[string]string) ([]Mismatch, error) {
+	var mismatches []Mismatch
+	for _, name := range l.names(constraints) {
+		rs, requested := constraints[name]
+		e, locked := l.Packages[name]
+		if !requested {
+			mismatches = append(mismatches, Mismatch{name, "locked but no longer requested"})
+			continue
+		}
+		if !locked {
+			mismatches = append(mismatches, Mismatch{name, "not locked"})
+			continue
+		}
+		r, err := semv.ParseRange(rs)
+		if err != nil {
+			return nil, fmt.Errorf("parsing range for %s: %s", name, err)
+		}
+		if !r.SatisfiedBy(e.Version) {
+			mismatches = append(mismatches, Mismatch{name,
+				fmt.Sprintf("locked version %s does not satisfy %s", e.Version, rs)})
+		}
+	}
+	return mismatches, nil
+}
+
+// Update returns a copy of the lockfile updated to satisfy constraints,
+// along with the list of packages that changed. Packages whose locked version
+// still satisfies the requested range are left exactly as they are, only
+// having their recorded range updated. All other packages are set to the
+// greatest version available from src that satisfies their range, and have
+// their integrity recomputed using hash, if it is not nil. Packages which are
+// no longer requested are removed.
+func (l *Lockfile) Update(constraints map[string]string, src Source, hash Hasher) (*Lockfile, []Change, error) {
+	updated := New()
+	var changes []Change
+	for _, name := range l.names(constraints) {
+		rs, requested := constraints[name]
+		e, locked := l.Packages[name]
+		if !requested {
+			old := e.Version
+			changes = append(changes, Change{Name: name, Old: &old})
+			continue
+		}
+		r, err := semv.ParseRange(rs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing range for %s: %s", name, err)
+		}
+		if locked && r.SatisfiedBy(e.Version) {
+			e.Range = rs
+			updated.Packages[name] = e
+			continue
+		}
+		available, err := src.Versions(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		v, ok := available.GreatestSatisfying(r)
+		if !ok {
+			return nil, nil, fmt.Errorf("no version of %s satisfies %s", name, rs)
+		}
+		var integrity string
+		if hash != nil {
+			if integrity, err = hash(name, v); err != nil {
+				return nil, nil, err
+			}
+		}
+		updated.Set(name, rs, v, integrity)
+		c := Change{Name: name, New: &v}
+		if locked {
+			old := e.Version
+			c.Old = &old
+		}
+		changes = append(changes, c)
+	}
+	return updated, changes, nil
+}
+
+// names returns the sorted union of locked package names and the names in
+// constraints.
+func (l *Lockfile) names(constraints map[string]string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for name := range l.Packages {
+		seen[name] = true
+		names = append(names, name)
+	}
+	for name := range constraints {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,150 @@
+package lockfile
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/samsalisbury/semv"
+)
+
+type memorySource map[string]semv.VersionList
+
+func (s memorySource) Versions(name string) (semv.VersionList, error) {
+	return s[name], nil
+}
+
+var source = memorySource{
+	"log":  semv.MustParseList("1.0.0", "1.1.0", "1.2.0", "2.0.0"),
+	"http": semv.MustParseList("2.0.0", "2.1.0", "3.0.0"),
+	"yaml": semv.MustParseList("0.1.0", "0.2.0"),
+}
+
+func newLockfile() *Lockfile {
+	l := New()
+	l.Set("log", "^1.0.0", semv.MustParse("1.1.0"), Integrity([]byte("log-1.1.0")))
+	l.Set("http", "^2.0.0", semv.MustParse("2.0.0"), Integrity([]byte("http-2.0.0")))
+	return l
+}
+
+func TestReadWrite(t *testing.T) {
+	expected := newLockfile()
+	buf := &bytes.Buffer{}
+	if err := expected.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	written := buf.String()
+	actual, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("read %+v; want %+v", actual, expected)
+	}
+	if !strings.Contains(written, `"version": "1.1.0"`) {
+		t.Errorf("unexpected lockfile contents:\n%s", written)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "semv.lock")
+	expected := newLockfile()
+	if err := expected.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("loaded %+v; want %+v", actual, expected)
+	}
+}
+
+func TestRead_UnsupportedFormat(t *testing.T) {
+	_, err := Read(strings.NewReader(`{"lockfileVersion": 99, "packages": {}}`))
+	if _, ok := err.(UnsupportedFormat); !ok {
+		t.Errorf("got error %v; want UnsupportedFormat", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	e := newLockfile().Packages["log"]
+	if !e.Verify([]byte("log-1.1.0")) {
+		t.Errorf("expected matching content to verify")
+	}
+	if e.Verify([]byte("log-1.2.0")) {
+		t.Errorf("expected different content not to verify")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	l := newLockfile()
+	mismatches, err := l.Check(map[string]string{"log": ">=1.1.0", "http": "^2.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) != 0 {
+		t.Errorf("got mismatches %v; want none", mismatches)
+	}
+	mismatches, err = l.Check(map[string]string{"log": "^1.2.0", "yaml": "^0.1.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"http: locked but no longer requested",
+		"log: locked version 1.1.0 does not satisfy ^1.2.0",
+		"yaml: not locked",
+	}
+	if len(mismatches) != len(expected) {
+		t.Fatalf("got mismatches %v; want %v", mismatches, expected)
+	}
+	for i, m := range mismatches {
+		if m.String() != expected[i] {
+			t.Errorf("got mismatch %q; want %q", m, expected[i])
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	l := newLockfile()
+	hash := func(name string, v semv.Version) (string, error) {
+		return Integrity([]byte(name + "-" + v.String())), nil
+	}
+	constraints := map[string]string{"log": ">=1.0.0", "http": "^3.0.0", "yaml": "~0.1.0"}
+	updated, changes, err := l.Update(constraints, source, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// log still satisfies its new range, so it must not move.
+	if e := updated.Packages["log"]; e.Version.String() != "1.1.0" || e.Range != ">=1.0.0" {
+		t.Errorf("got log entry %+v; want 1.1.0 locked with range >=1.0.0", e)
+	}
+	if e := updated.Packages["http"]; !e.Verify([]byte("http-3.0.0")) {
+		t.Errorf("got http entry %+v; want 3.0.0 with matching integrity", e)
+	}
+	expected := []string{"http: 2.0.0 -> 3.0.0", "yaml: added 0.1.0"}
+	if len(changes) != len(expected) {
+		t.Fatalf("got changes %v; want %v", changes, expected)
+	}
+	for i, c := range changes {
+		if c.String() != expected[i] {
+			t.Errorf("got change %q; want %q", c, expected[i])
+		}
+	}
+	if mismatches, _ := updated.Check(constraints); len(mismatches) != 0 {
+		t.Errorf("updated lockfile has mismatches %v", mismatches)
+	}
+	if l.Packages["http"].Version.String() != "2.0.0" {
+		t.Errorf("Update modified the original lockfile")
+	}
+}
+
+func TestUpdate_Unsatisfiable(t *testing.T) {
+	_, _, err := newLockfile().Update(map[string]string{"log": "^9.0.0"}, source, nil)
+	if err == nil {
+		t.Errorf("expected an error updating to an unsatisfiable range")
+	}
+}
@@ -0,0 +1,226 @@
+package semv
+
+import (
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BuildMetadata is the build metadata of a version, split into its
+// dot-separated identifiers. Semver gives the identifiers no meaning, but by
+// convention they are often key/value pairs, so for example
+// "build.542.sha.abc123" has the build number 542 and the commit SHA abc123.
+type BuildMetadata []string
+
+const (
+	// BuildNumberKey is the key of the build number, e.g. "build.542".
+	BuildNumberKey = "build"
+	// RevisionKey is the key of the VCS revision, e.g. "sha.abc123".
+	RevisionKey = "sha"
+	// DateKey is the key of the build or commit date, in the form
+	// yyyymmddhhmmss, in UTC, e.g. "date.20240102150405".
+	DateKey = "date"
+	// DirtyIdentifier is a lone identifier marking a build from a working
+	// tree with uncommitted changes.
+	DirtyIdentifier = "dirty"
+	// dateLayout is the time layout of the value of DateKey.
+	dateLayout = "20060102150405"
+	// revisionLength is the number of characters of VCS revisions recorded
+	// by WithBuildInfo, as used in Go pseudo-versions.
+	revisionLength = 12
+)
+
+// BuildMetadata returns the metadata field split into its identifiers, or nil
+// if it is empty.
+func (v Version) BuildMetadata() BuildMetadata {
+	if v.Meta == "" {
+		return nil
+	}
+	return strings.Split(v.Meta, ".")
+}
+
+// WithBuildMetadata returns a new version with the metadata field set to m.
+// Unlike SetMeta, if the version's DefaultFormat omits the metadata, as when
+// it was parsed without metadata, it is extended so that String includes the
+// new metadata.
+func (v Version) WithBuildMetadata(m BuildMetadata) Version {
+	v.Meta = m.String()
+	if v.Meta == "" || v.DefaultFormat == "" || v.Format(v.DefaultFormat) != v.SetMeta("").Format(v.DefaultFormat) {
+		return v
+	}
+	if isFormatLanguage(v.DefaultFormat) {
+		v.DefaultFormat += "+%b"
+	} else {
+		v.DefaultFormat += Meta
+	}
+	return v
+}
+
+// String joins the identifiers with dots, as they appear in a version.
+func (m BuildMetadata) String() string {
+	return strings.Join(m, ".")
+}
+
+// Get returns the identifier following the first identifier equal to key. If
+// there is no such identifier, the second return value is false.
+func (m BuildMetadata) Get(key string) (string, bool) {
+	for i := 0; i < len(m)-1; i++ {
+		if m[i] == key {
+			return m[i+1], true
+		}
+	}
+	return "", false
+}
+
+// Has returns true if any identifier equals id.
+func (m BuildMetadata) Has(id string) bool {
+	for _, x := range m {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Set returns a copy of m in which the value of key is value. If key already
+// has a value, it is replaced in place, otherwise key and value are appended.
+func (m BuildMetadata) Set(key, value string) BuildMetadata {
+	out := append(BuildMetadata(nil), m...)
+	for i := 0; i < len(out)-1; i++ {
+		if out[i] == key {
+			out[i+1] = value
+			return out
+		}
+	}
+	return append(out, key, value)
+}
+
+// BuildNumber returns the value of BuildNumberKey as an int. If there is no
+// such value, or it is not a number, the second return value is false.
+func (m BuildMetadata) BuildNumber() (int, bool) {
+	s, ok := m.Get(BuildNumberKey)
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil && n >= 0
+}
+
+// Revision returns the value of RevisionKey, or the empty string if there is
+// none.
+func (m BuildMetadata) Revision() string {
+	s, _ := m.Get(RevisionKey)
+	return s
+}
+
+// Date returns the value of DateKey as a time in UTC. If there is no such
+// value, or it is not in the form yyyymmddhhmmss, the second return value is
+// false.
+func (m BuildMetadata) Date() (time.Time, bool) {
+	s, ok := m.Get(DateKey)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(dateLayout, s)
+	return t, err == nil
+}
+
+// Dirty returns true if m includes DirtyIdentifier.
+func (m BuildMetadata) Dirty() bool {
+	return m.Has(DirtyIdentifier)
+}
+
+// WithBuildInfo returns a new version with metadata describing the VCS
+// revision the running program was built from, as recorded by the go command
+// and returned by debug.ReadBuildInfo: the first 12 characters of the
+// revision, the commit time, and DirtyIdentifier if the working tree had
+// uncommitted changes. For example, "1.2.3+build.542" might become
+// "1.2.3+build.542.sha.abc123def456.date.20240102150405". Existing metadata is
+// kept, except for any previous values of RevisionKey and DateKey. If there is
+// no build information, the version is returned unchanged.
+func (v Version) WithBuildInfo() Version {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return v
+	}
+	return v.WithBuildSettings(info.Settings)
+}
+
+// WithBuildSettings is like WithBuildInfo, but takes the VCS settings from
+// settings, e.g. the Settings of a debug.BuildInfo read from another binary
+// using debug.ReadBuildInfo or buildinfo.ReadFile. Settings other than
+// vcs.revision, vcs.time and vcs.modified are ignored.
+func (v Version) WithBuildSettings(settings []debug.BuildSetting) Version {
+	m := v.BuildMetadata()
+	dirty := false
+	for _, s := range settings {
+		switch s.Key {
+		case "vcs.revision":
+			rev := s.Value
+			if len(rev) > revisionLength {
+				rev = rev[:revisionLength]
+			}
+			m = m.Set(RevisionKey, rev)
+		case "vcs.time":
+			if t, err := time.Parse(time.RFC3339, s.Value); err == nil {
+				m = m.Set(DateKey, t.UTC().Format(dateLayout))
+			}
+		case "vcs.modified":
+			dirty = s.Value == "true"
+		}
+	}
+	if dirty && !m.Has(DirtyIdentifier) {
+		m = append(m, DirtyIdentifier)
+	}
+	return v.WithBuildMetadata(m)
+}
+
+// ExactCompare returns -1, 0 or 1 if a is less than, equal to, or greater
+// than b. Versions are ordered by precedence, as by Less, and then, unlike
+// Less, by their metadata, so that sorting is deterministic. Versions without
+// metadata come first, and then metadata is compared identifier by
+// identifier, in the same way as prerelease identifiers, so that
+// "1.0.0+build.9" is less than "1.0.0+build.10". Lastly, versions are
+// ordered by String, so ExactCompare returns 0 only if ExactEquals is true.
+func ExactCompare(a, b Version) int {
+	switch {
+	case a.Less(b):
+		return -1
+	case b.Less(a):
+		return 1
+	}
+	if c := compareMetadata(a.Meta, b.Meta); c != 0 {
+		return c
+	}
+	return strings.Compare(a.String(), b.String())
+}
+
+// compareMetadata compares the metadata fields of two versions, returning -1,
+// 0 or 1.
+func compareMetadata(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return -1
+	case b == "":
+		return 1
+	}
+	for {
+		aID, aNext, aMore := strings.Cut(a, ".")
+		bID, bNext, bMore := strings.Cut(b, ".")
+		if c := comparePreIdentifiers(aID, bID); c != 0 {
+			return c
+		}
+		switch {
+		case !aMore && !bMore:
+			return 0
+		case !aMore:
+			return -1
+		case !bMore:
+			return 1
+		}
+		a, b = aNext, bNext
+	}
+}
@@ -0,0 +1,163 @@
+package semv
+
+import (
+	"runtime/debug"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildMetadata(t *testing.T) {
+	m := MustParse("1.2.3+build.542.sha.abc123.date.20240102150405.dirty").BuildMetadata()
+	if n, ok := m.BuildNumber(); !ok || n != 542 {
+		t.Errorf("got BuildNumber() == %d, %t; want 542, true", n, ok)
+	}
+	if rev := m.Revision(); rev != "abc123" {
+		t.Errorf("got Revision() == %q; want %q", rev, "abc123")
+	}
+	if d, ok := m.Date(); !ok || !d.Equal(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)) {
+		t.Errorf("got Date() == %s, %t; want 2024-01-02 15:04:05 UTC, true", d, ok)
+	}
+	if !m.Dirty() {
+		t.Errorf("got Dirty() == false; want true")
+	}
+	if _, ok := m.Get("dirty"); ok {
+		t.Errorf("got a value for the trailing identifier dirty")
+	}
+}
+
+func TestBuildMetadata_Missing(t *testing.T) {
+	m := MustParse("1.2.3+build.x").BuildMetadata()
+	if _, ok := m.BuildNumber(); ok {
+		t.Errorf("got a build number from build.x")
+	}
+	if _, ok := m.Date(); ok {
+		t.Errorf("got a date from metadata without one")
+	}
+	if m.Revision() != "" || m.Dirty() {
+		t.Errorf("got Revision() == %q, Dirty() == %t; want empty, false", m.Revision(), m.Dirty())
+	}
+	if m := MustParse("1.2.3").BuildMetadata(); m != nil {
+		t.Errorf("got %q; want nil", m)
+	}
+}
+
+func TestBuildMetadata_Set(t *testing.T) {
+	m := BuildMetadata{"build", "1"}
+	tests := []struct {
+		m          BuildMetadata
+		key, value string
+		expected   string
+	}{
+		{nil, "build", "1", "build.1"},
+		{m, "build", "2", "build.2"},
+		{m, "sha", "abc", "build.1.sha.abc"},
+	}
+	for _, test := range tests {
+		if actual := test.m.Set(test.key, test.value).String(); actual != test.expected {
+			t.Errorf("%q.Set(%q, %q): got %q; want %q", test.m, test.key, test.value, actual, test.expected)
+		}
+	}
+	if m.String() != "build.1" {
+		t.Errorf("Set modified the original metadata: got %q", m)
+	}
+}
+
+func TestVersion_WithBuildMetadata(t *testing.T) {
+	tests := map[string]string{
+		"1.2.3":         "1.2.3+build.7",
+		"1.2":           "1.2+build.7",
+		"1.2.3+build.6": "1.2.3+build.7",
+		"1.2.3-rc.1+a":  "1.2.3-rc.1+a.build.7",
+	}
+	for input, expected := range tests {
+		v := MustParse(input)
+		if actual := v.WithBuildMetadata(v.BuildMetadata().Set("build", "7")).String(); actual != expected {
+			t.Errorf("%s: got %q; want %q", input, actual, expected)
+		}
+	}
+	if actual := NewMajorMinorPatch(1, 0, 0).WithBuildMetadata(BuildMetadata{"a"}).String(); actual != "1.0.0+a" {
+		t.Errorf("got %q; want %q", actual, "1.0.0+a")
+	}
+	formats := map[string]string{
+		"%M.%m.%p":           "1.2.3+build.7",
+		"%M.%m.%p{-%r}":      "1.2.3-rc.1+build.7",
+		"%M.%m.%p{-%r}{+%b}": "1.2.3-rc.1+build.7",
+		"%M.%m.%p+":          "1.2.3++build.7",
+		"v%M.%m":             "v1.2+build.7",
+	}
+	for format, expected := range formats {
+		v := MustParse("1.2.3-rc.1")
+		v.DefaultFormat = format
+		if actual := v.WithBuildMetadata(BuildMetadata{"build", "7"}).String(); actual != expected {
+			t.Errorf("%s: got %q; want %q", format, actual, expected)
+		}
+	}
+}
+
+func TestVersion_WithBuildSettings(t *testing.T) {
+	settings := []debug.BuildSetting{
+		{Key: "vcs", Value: "git"},
+		{Key: "vcs.revision", Value: "0123456789abcdef0123456789abcdef01234567"},
+		{Key: "vcs.time", Value: "2024-01-02T16:04:05+01:00"},
+		{Key: "vcs.modified", Value: "true"},
+	}
+	tests := map[string]string{
+		"1.2.3":                  "1.2.3+sha.0123456789ab.date.20240102150405.dirty",
+		"1.2.3+build.5.sha.old1": "1.2.3+build.5.sha.0123456789ab.date.20240102150405.dirty",
+		"1.2.3+dirty":            "1.2.3+dirty.sha.0123456789ab.date.20240102150405",
+	}
+	for input, expected := range tests {
+		if actual := MustParse(input).WithBuildSettings(settings).String(); actual != expected {
+			t.Errorf("%s: got %q; want %q", input, actual, expected)
+		}
+	}
+	if actual := MustParse("1.2.3").WithBuildSettings(settings[:2]).String(); actual != "1.2.3+sha.0123456789ab" {
+		t.Errorf("got %q; want %q", actual, "1.2.3+sha.0123456789ab")
+	}
+	if actual := MustParse("1.2.3").WithBuildSettings(nil).String(); actual != "1.2.3" {
+		t.Errorf("got %q; want %q", actual, "1.2.3")
+	}
+}
+
+func TestExactCompare(t *testing.T) {
+	// Each version is less than the next.
+	ordered := []string{
+		"1.0.0-rc.1+b",
+		"1.0.0",
+		"1.0.0+0",
+		"1.0.0+build.9",
+		"1.0.0+build.10",
+		"1.0.0+build.10.a",
+		"1.0.0+build.a",
+		"1.0.0+sha.abc",
+		"1.0.1",
+	}
+	for i, a := range ordered {
+		for j, b := range ordered {
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			if actual := ExactCompare(MustParse(a), MustParse(b)); actual != expected {
+				t.Errorf("ExactCompare(%s, %s): got %d; want %d", a, b, actual, expected)
+			}
+		}
+	}
+	if actual := ExactCompare(MustParse("1.0"), MustParse("1.0.0")); actual == 0 {
+		t.Errorf("ExactCompare(1.0, 1.0.0): got 0; want non-zero, since they are not ExactEquals")
+	}
+}
+
+func TestVersionList_SortedExact(t *testing.T) {
+	vl := MustParseList("1.0.0+build.10", "1.0.0+build.9", "0.9.0", "1.0.0")
+	var actual []string
+	for _, v := range vl.SortedExact() {
+		actual = append(actual, v.String())
+	}
+	if expected := "0.9.0 1.0.0 1.0.0+build.9 1.0.0+build.10"; strings.Join(actual, " ") != expected {
+		t.Errorf("got %q; want %q", strings.Join(actual, " "), expected)
+	}
+}
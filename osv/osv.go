@@ -0,0 +1,345 @@
+/*
+Package osv matches versions against security advisories in the OSV format,
+described at https://ossf.github.io/osv-schema/.
+
+Only ranges of type SEMVER are interpreted; ranges of other types, such as
+ECOSYSTEM or GIT, are ignored. Versions listed explicitly in an affected
+package's "versions" field are also matched, where they parse as versions.
+*/
+package osv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/samsalisbury/semv"
+)
+
+// SemverRange is the OSV range type whose events are semver versions.
+const SemverRange = "SEMVER"
+
+type (
+	// Advisory is a single OSV vulnerability entry.
+	Advisory struct {
+		ID       string     `json:"id"`
+		Aliases  []string   `json:"aliases,omitempty"`
+		Summary  string     `json:"summary,omitempty"`
+		Details  string     `json:"details,omitempty"`
+		Affected []Affected `json:"affected"`
+	}
+	// Affected describes the versions of a single package affected by an
+	// advisory.
+	Affected struct {
+		Package  Package  `json:"package"`
+		Ranges   []Range  `json:"ranges,omitempty"`
+		Versions []string `json:"versions,omitempty"`
+		// parsed caches the versions parsed from Ranges and Versions. It is
+		// set by Read, and shared by copies of the Affected.
+		parsed *parsedAffected
+	}
+	// Package identifies a package within an ecosystem.
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	}
+	// Range is an OSV range: a list of events which, taken in version
+	// order, introduce and fix the vulnerability.
+	Range struct {
+		Type   string  `json:"type"`
+		Events []Event `json:"events"`
+	}
+	// Event is a single OSV range event. Exactly one field is set.
+	Event struct {
+		Introduced   string `json:"introduced,omitempty"`
+		Fixed        string `json:"fixed,omitempty"`
+		LastAffected string `json:"last_affected,omitempty"`
+		Limit        string `json:"limit,omitempty"`
+	}
+	// Finding is a single advisory affecting a version of a package, along
+	// with the lowest version fixing it, if any.
+	Finding struct {
+		Advisory *Advisory
+		// Fix is the lowest version greater than the one checked which is
+		// not affected, or nil if no fix is known.
+		Fix *semv.Version
+	}
+	// Database is a collection of advisories.
+	Database []*Advisory
+	// parsedAffected holds the versions of an Affected, parsed.
+	parsedAffected struct {
+		ranges semv.RangeSet
+		listed semv.VersionList
+		fixes  semv.VersionList
+		// invalid is true if the SEMVER ranges could not be converted.
+		invalid bool
+	}
+)
+
+// Read reads a single advisory from r, and checks that all of its SEMVER
+// ranges are valid. The versions of each affected package are parsed once,
+// here, so the Affected entries should not be modified afterwards. Advisories
+// built in code are parsed on every check instead.
+func Read(r io.Reader) (*Advisory, error) {
+	a := &Advisory{}
+	if err := json.NewDecoder(r).Decode(a); err != nil {
+		return nil, err
+	}
+	for i, affected := range a.Affected {
+		rs, err := affected.RangeSet()
+		if err != nil {
+			return nil, fmt.Errorf("advisory %s: %s", a.ID, err)
+		}
+		a.Affected[i].parsed = affected.parse(rs)
+	}
+	return a, nil
+}
+
+// Load reads a single advisory from the file at path.
+func Load(path string) (*Advisory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	a, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return a, nil
+}
+
+// LoadDir reads every file with a .json extension in dir as an advisory.
+func LoadDir(dir string) (Database, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	db := make(Database, 0, len(paths))
+	for _, path := range paths {
+		a, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		db = append(db, a)
+	}
+	return db, nil
+}
+
+// RangeSet converts the events of this range into a RangeSet. Each
+// "introduced" event starts a range, which is ended by the next "fixed"
+// (exclusive) or "last_affected" (inclusive) event. An introduced version of
+// "0" means the range has no lower bound, and a range which is never ended
+// has no upper bound. Any "limit" events are applied as an exclusive upper
+// bound on every range.
+func (r Range) RangeSet() (semv.RangeSet, error) {
+	if r.Type != SemverRange {
+		return nil, fmt.Errorf("cannot convert %s range to semver ranges", r.Type)
+	}
+	events := r.Events
+	versions := make(map[int]semv.Version, len(events))
+	for i, e := range events {
+		s := e.value()
+		if s == "0" && e.Introduced != "" {
+			continue
+		}
+		v, err := semv.ParseExactSemver2(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q in event: %s", s, err)
+		}
+		versions[i] = v
+	}
+	order := make([]int, len(events))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		vi, iOK := versions[order[i]]
+		vj, jOK := versions[order[j]]
+		if !iOK || !jOK {
+			return !iOK && jOK
+		}
+		return vi.Less(vj)
+	})
+	var rs semv.RangeSet
+	var limits []semv.Range
+	var open *semv.Range
+	for _, i := range order {
+		e, v := events[i], versions[i]
+		switch {
+		case e.Introduced != "":
+			if open != nil {
+				continue
+			}
+			open = &semv.Range{}
+			if e.Introduced != "0" {
+				open.MinEqual = &v
+			}
+		case e.Fixed != "":
+			if open != nil {
+				open.Max = &v
+				rs, open = append(rs, *open), nil
+			}
+		case e.LastAffected != "":
+			if open != nil {
+				open.MaxEqual = &v
+				rs, open = append(rs, *open), nil
+			}
+		case e.Limit != "":
+			limits = append(limits, semv.LessThan(v))
+		}
+	}
+	if open != nil {
+		rs = append(rs, *open)
+	}
+	for _, limit := range limits {
+		rs = rs.Intersect(semv.RangeSet{limit})
+	}
+	return rs, nil
+}
+
+// value returns whichever field of the event is set.
+func (e Event) value() string {
+	switch {
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	case e.LastAffected != "":
+		return e.LastAffected
+	}
+	return e.Limit
+}
+
+// RangeSet returns the union of all the SEMVER ranges of this affected
+// package. Ranges of other types are ignored.
+func (a Affected) RangeSet() (semv.RangeSet, error) {
+	var rs semv.RangeSet
+	for _, r := range a.Ranges {
+		if r.Type != SemverRange {
+			continue
+		}
+		converted, err := r.RangeSet()
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, converted...)
+	}
+	return rs, nil
+}
+
+// Affects returns true if version v of this package is affected, either
+// because it falls within one of the SEMVER ranges, or because it is listed
+// explicitly. Prereleases are treated like any other version, so a range of
+// >=1.0.0 <2.0.0 affects 1.5.0-beta. If the SEMVER ranges are malformed, which
+// Read rejects but Affected values built in code may be, every version is
+// affected, since it is unknown which are safe.
+func (a Affected) Affects(v semv.Version) bool {
+	p := a.versions()
+	if p.invalid || p.ranges.Contains(v) {
+		return true
+	}
+	for _, listed := range p.listed {
+		if listed.Equals(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// versions returns the parsed versions of this affected package, parsing
+// them if Read did not.
+func (a Affected) versions() *parsedAffected {
+	if a.parsed != nil {
+		return a.parsed
+	}
+	rs, err := a.RangeSet()
+	p := a.parse(rs)
+	p.invalid = err != nil
+	return p
+}
+
+// parse parses the listed versions and fixes of this affected package, which
+// has the SEMVER ranges rs. Versions which do not parse are ignored.
+func (a Affected) parse(rs semv.RangeSet) *parsedAffected {
+	p := &parsedAffected{ranges: rs}
+	for _, s := range a.Versions {
+		if v, err := semv.Parse(s); err == nil {
+			p.listed = append(p.listed, v)
+		}
+	}
+	for _, r := range a.Ranges {
+		if r.Type != SemverRange {
+			continue
+		}
+		for _, e := range r.Events {
+			if v, err := semv.Parse(e.Fixed); e.Fixed != "" && err == nil {
+				p.fixes = append(p.fixes, v)
+			}
+		}
+	}
+	return p
+}
+
+// matching returns the affected entries of this advisory for the named
+// package in the given ecosystem.
+func (a *Advisory) matching(ecosystem, name string) []Affected {
+	var out []Affected
+	for _, affected := range a.Affected {
+		if affected.Package.Ecosystem == ecosystem && affected.Package.Name == name {
+			out = append(out, affected)
+		}
+	}
+	return out
+}
+
+// Affects returns true if version v of the named package is affected by this
+// advisory.
+func (a *Advisory) Affects(ecosystem, name string, v semv.Version) bool {
+	for _, affected := range a.matching(ecosystem, name) {
+		if affected.Affects(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// LowestFix returns the lowest version greater than v, at which the advisory
+// records a fix, which is itself not affected by this advisory. If v is not
+// affected, or no such fix is known, the second return value is false.
+func (a *Advisory) LowestFix(ecosystem, name string, v semv.Version) (semv.Version, bool) {
+	if !a.Affects(ecosystem, name, v) {
+		return semv.Version{}, false
+	}
+	var fixes semv.VersionList
+	for _, affected := range a.matching(ecosystem, name) {
+		fixes = append(fixes, affected.versions().fixes...)
+	}
+	for _, fix := range fixes.Sorted() {
+		if v.Less(fix) && !a.Affects(ecosystem, name, fix) {
+			return fix, true
+		}
+	}
+	return semv.Version{}, false
+}
+
+// Check returns a Finding for each advisory in the database affecting
+// version v of the named package, in the order the advisories appear.
+func (db Database) Check(ecosystem, name string, v semv.Version) []Finding {
+	var findings []Finding
+	for _, a := range db {
+		if !a.Affects(ecosystem, name, v) {
+			continue
+		}
+		f := Finding{Advisory: a}
+		if fix, ok := a.LowestFix(ecosystem, name, v); ok {
+			f.Fix = &fix
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
@@ -0,0 +1,121 @@
+package osv
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/samsalisbury/semv"
+)
+
+func loadTestdata(t *testing.T) Database {
+	db, err := LoadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(db) != 3 {
+		t.Fatalf("loaded %d advisories; want 3", len(db))
+	}
+	return db
+}
+
+func TestRangeSet(t *testing.T) {
+	db := loadTestdata(t)
+	tests := map[string]string{
+		"GHSA-0001": "<1.2.3 || >=2.0.0 <2.1.1",
+		"GHSA-0002": "^2.1.0",
+		"GHSA-0003": ">=0.5.0 <0.9.0",
+	}
+	for _, a := range db {
+		rs, err := a.Affected[0].RangeSet()
+		if err != nil {
+			t.Errorf("%s: %s", a.ID, err)
+			continue
+		}
+		if rs.String() != tests[a.ID] {
+			t.Errorf("%s: got ranges %q; want %q", a.ID, rs, tests[a.ID])
+		}
+	}
+}
+
+var checkTests = []struct {
+	ecosystem, name, version string
+	expected                 string
+}{
+	{"Go", "example.com/widget", "1.0.0", "[GHSA-0001 fixed in 1.2.3 GHSA-0002 fixed in 3.0.0]"},
+	{"Go", "example.com/widget", "1.2.2", "[GHSA-0001 fixed in 1.2.3]"},
+	{"Go", "example.com/widget", "1.2.3", "[]"},
+	{"Go", "example.com/widget", "2.0.0-beta", "[]"},
+	{"Go", "example.com/widget", "2.0.5", "[GHSA-0001 fixed in 2.1.1]"},
+	{"Go", "example.com/widget", "2.1.0", "[GHSA-0001 fixed in 2.1.1 GHSA-0002 fixed in 3.0.0]"},
+	{"Go", "example.com/widget", "2.5.0-rc.1", "[GHSA-0002 fixed in 3.0.0]"},
+	{"npm", "widget", "1.4.0", "[GHSA-0002 not fixed]"},
+	{"npm", "widget", "1.4.1", "[]"},
+	{"Go", "example.com/gadget", "0.8.9", "[GHSA-0003 not fixed]"},
+	{"Go", "example.com/gadget", "0.9.0", "[]"},
+	{"Go", "example.com/other", "1.0.0", "[]"},
+}
+
+func TestCheck(t *testing.T) {
+	db := loadTestdata(t)
+	for _, test := range checkTests {
+		findings := db.Check(test.ecosystem, test.name, semv.MustParse(test.version))
+		summary := make([]string, len(findings))
+		for i, f := range findings {
+			if f.Fix == nil {
+				summary[i] = f.Advisory.ID + " not fixed"
+			} else {
+				summary[i] = f.Advisory.ID + " fixed in " + f.Fix.String()
+			}
+		}
+		if actual := fmt.Sprint(summary); actual != test.expected {
+			t.Errorf("checking %s %s@%s: got %s; want %s",
+				test.ecosystem, test.name, test.version, actual, test.expected)
+		}
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	if _, err := Load(filepath.Join("testdata", "invalid", "bad-version.json")); err == nil {
+		t.Errorf("expected error loading advisory with invalid version")
+	}
+	if _, err := Load(filepath.Join("testdata", "missing.json")); err == nil {
+		t.Errorf("expected error loading missing file")
+	}
+}
+
+func TestAffects_Parsed(t *testing.T) {
+	for _, a := range loadTestdata(t) {
+		for i, affected := range a.Affected {
+			if affected.parsed == nil {
+				t.Errorf("%s: affected[%d] was not parsed when loaded", a.ID, i)
+			}
+		}
+	}
+	// Affected entries built in code are parsed when checked.
+	affected := Affected{
+		Ranges:   []Range{{Type: SemverRange, Events: []Event{{Introduced: "1.0.0"}, {Fixed: "1.1.0"}}}},
+		Versions: []string{"0.9.0", "not-a-version"},
+	}
+	for vs, expected := range map[string]bool{"1.0.5": true, "0.9.0": true, "1.1.0": false, "0.8.0": false} {
+		if actual := affected.Affects(semv.MustParse(vs)); actual != expected {
+			t.Errorf("Affects(%s): got %t; want %t", vs, actual, expected)
+		}
+	}
+}
+
+func TestAffects_Malformed(t *testing.T) {
+	// Malformed ranges fail closed, affecting every version.
+	affected := Affected{
+		Ranges: []Range{{Type: SemverRange, Events: []Event{{Introduced: "1.0.0"}, {Fixed: "not-a-version"}}}},
+	}
+	for _, vs := range []string{"0.1.0", "1.0.0", "9.9.9"} {
+		if !affected.Affects(semv.MustParse(vs)) {
+			t.Errorf("Affects(%s): got false; want true for malformed ranges", vs)
+		}
+	}
+	db := Database{{ID: "GHSA-bad", Affected: []Affected{{Package: Package{"Go", "bad"}, Ranges: affected.Ranges}}}}
+	if findings := db.Check("Go", "bad", semv.MustParse("2.0.0")); len(findings) != 1 || findings[0].Fix != nil {
+		t.Errorf("got %v; want one finding with no fix", findings)
+	}
+}
@@ -0,0 +1,100 @@
+package semv
+
+import (
+	"fmt"
+	"sync"
+)
+
+type (
+	// BatchOptions configure ParseBatch. The zero BatchOptions parses
+	// sequentially using Parse, and does not keep the original strings.
+	BatchOptions struct {
+		// Parse is the func used to parse each string. If it is nil, Parse
+		// is used.
+		Parse func(string) (Version, error)
+		// KeepOriginal causes the original string of each successfully
+		// parsed version to be recorded in BatchResult.Originals.
+		KeepOriginal bool
+		// Workers is the number of goroutines used to parse the input. If
+		// it is less than 2, the input is parsed sequentially. The result is
+		// the same however many workers are used.
+		Workers int
+	}
+	// BatchResult is the result of ParseBatch.
+	BatchResult struct {
+		// Versions are the successfully parsed versions, in input order.
+		Versions VersionList
+		// Originals are the strings each of Versions was parsed from, at
+		// the same index, if BatchOptions.KeepOriginal was set.
+		Originals []string
+		// Failures describe each string which failed to parse, in input
+		// order.
+		Failures []ParseFailure
+	}
+	// ParseFailure describes a string which ParseBatch failed to parse.
+	ParseFailure struct {
+		// Index is the position of the string in the input.
+		Index int
+		// Input is the string which failed to parse.
+		Input string
+		// Err is the error returned by the parser.
+		Err error
+	}
+)
+
+func (f ParseFailure) Error() string {
+	return fmt.Sprintf("input %d: parsing %q: %s", f.Index, f.Input, f.Err)
+}
+
+// Unwrap returns the underlying parse error.
+func (f ParseFailure) Unwrap() error { return f.Err }
+
+// ParseBatch parses each of the strings passed in, and unlike ParseList, does
+// not stop at the first failure. Instead it returns all of the versions which
+// parsed successfully, along with a ParseFailure for each string which did
+// not, so that junk such as "latest" in a list of tags can be reported and
+// skipped.
+func ParseBatch(versions []string, opts BatchOptions) BatchResult {
+	parseFunc := opts.Parse
+	if parseFunc == nil {
+		parseFunc = Parse
+	}
+	parsed := make(VersionList, len(versions))
+	errs := make([]error, len(versions))
+	parseRange := func(start, end int) {
+		for i := start; i < end; i++ {
+			parsed[i], errs[i] = parseFunc(versions[i])
+		}
+	}
+	if opts.Workers < 2 {
+		parseRange(0, len(versions))
+	} else {
+		chunk := (len(versions) + opts.Workers - 1) / opts.Workers
+		var wg sync.WaitGroup
+		for start := 0; start < len(versions); start += chunk {
+			end := min(start+chunk, len(versions))
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				parseRange(start, end)
+			}()
+		}
+		wg.Wait()
+	}
+
+	result := BatchResult{Versions: make(VersionList, 0, len(versions))}
+	if opts.KeepOriginal {
+		result.Originals = make([]string, 0, len(versions))
+	}
+	for i, err := range errs {
+		if err != nil {
+			result.Failures = append(result.Failures, ParseFailure{i, versions[i], err})
+			continue
+		}
+		result.Versions = append(result.Versions, parsed[i])
+		if opts.KeepOriginal {
+			result.Originals = append(result.Originals, versions[i])
+		}
+	}
+	return result
+}
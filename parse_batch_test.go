@@ -0,0 +1,59 @@
+package semv
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var batchInput = []string{"1.0.0", "latest", "1.2", "nightly", "2.1", "v1.2-foo_bar", "3.0.0-rc.1"}
+
+func TestParseBatch(t *testing.T) {
+	result := ParseBatch(batchInput, BatchOptions{})
+	assertVersionList(t, "ParseBatch", result.Versions, "1.0.0", "1.2", "2.1", "3.0.0-rc.1")
+	if result.Originals != nil {
+		t.Errorf("got originals %v; want nil", result.Originals)
+	}
+	var failures []string
+	for _, f := range result.Failures {
+		if f.Err == nil {
+			t.Errorf("failure %d has no error", f.Index)
+		}
+		failures = append(failures, fmt.Sprintf("%d:%s", f.Index, f.Input))
+	}
+	if s, expected := fmt.Sprint(failures), "[1:latest 3:nightly 5:v1.2-foo_bar]"; s != expected {
+		t.Errorf("got failures %s; want %s", s, expected)
+	}
+}
+
+func TestParseBatch_KeepOriginal(t *testing.T) {
+	input := []string{"v1.2.0", "latest", "release-2.0"}
+	result := ParseBatch(input, BatchOptions{Parse: ParseAny, KeepOriginal: true})
+	assertVersionList(t, "ParseBatch", result.Versions, "1.2.0", "2.0")
+	expected := []string{"v1.2.0", "release-2.0"}
+	if !reflect.DeepEqual(result.Originals, expected) {
+		t.Errorf("got originals %q; want %q", result.Originals, expected)
+	}
+}
+
+func TestParseBatch_Parse(t *testing.T) {
+	result := ParseBatch(batchInput, BatchOptions{Parse: ParseExactSemver2})
+	assertVersionList(t, "ParseBatch", result.Versions, "1.0.0", "3.0.0-rc.1")
+	if len(result.Failures) != 5 {
+		t.Errorf("got %d failures; want 5", len(result.Failures))
+	}
+}
+
+func TestParseBatch_Workers(t *testing.T) {
+	var input []string
+	for i := 0; i < 1000; i++ {
+		input = append(input, batchInput[i%len(batchInput)])
+	}
+	expected := ParseBatch(input, BatchOptions{KeepOriginal: true})
+	for _, workers := range []int{2, 3, 7, 64, 2000} {
+		actual := ParseBatch(input, BatchOptions{KeepOriginal: true, Workers: workers})
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%d workers gave a different result from sequential parsing", workers)
+		}
+	}
+}
@@ -1,10 +1,9 @@
 package semv
 
 import (
-	"bytes"
 	"fmt"
-	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // Parse permissively parses a string as a semver value. The minimal string
@@ -35,22 +34,14 @@ import (
 // If you want to validate that input is in exact semver 2.0.0 format, you
 // should use ParseExactSemver2 instead.
 func Parse(s string) (Version, error) {
-	v, errs := parse(s)
-	// Skip nil, LeadingZero, and VersionIncomplete errors in this
-	// permissive parse func.
-	for _, err := range errs {
-		if err == nil {
-			continue
-		}
-		if _, ok := err.(LeadingZero); ok {
-			continue
-		}
-		if _, ok := err.(VersionIncomplete); ok {
-			continue
-		}
-		return v, err
-	}
-	return v, nil
+	return parse(s, false)
+}
+
+// ParseBytes is like Parse, except that it parses a byte slice. Unless the
+// version has a prerelease or metadata field, or is invalid, it does not
+// allocate.
+func ParseBytes(b []byte) (Version, error) {
+	return parse(b, false)
 }
 
 // MustParse is like Parse, but panics on errors. This is useful when
@@ -72,8 +63,7 @@ func MustParse(s string) Version {
 //     LeadingZero when a major, minor, or patch contains an erroneous preceding zero character.
 //
 func ParseExactSemver2(s string) (Version, error) {
-	v, errs := parse(s)
-	return v, firstErr(errs...)
+	return parse(s, true)
 }
 
 // MustParseExactSemver2 is like ParseExactSemver2, excapt that
@@ -87,6 +77,42 @@ func MustParseExactSemver2(s string) Version {
 	return v
 }
 
+// ParseLossless is like Parse, except that it additionally accepts a leading
+// "v" or "V", and records the exact text parsed, so that String returns it
+// unchanged (see Original). This allows tools which edit files containing
+// versions to write back untouched versions byte-for-byte, e.g.
+// ParseLossless("v01.2").String() == "v01.2", whereas
+// Parse("01.2").String() == "1.2".
+//
+// Versions parsed by ParseLossless compare equal (using Equals) to the same
+// versions parsed by Parse, but not using ExactEquals or the == operator.
+func ParseLossless(s string) (Version, error) {
+	unprefixed := s
+	if len(s) != 0 && (s[0] == 'v' || s[0] == 'V') {
+		unprefixed = s[1:]
+	}
+	v, err := Parse(unprefixed)
+	if err != nil {
+		if uc, ok := err.(UnexpectedCharacter); ok {
+			uc.Pos += len(s) - len(unprefixed)
+			err = uc
+		}
+		return v, err
+	}
+	v.original = s
+	v.fingerprint = v.fingerprintFields()
+	return v, nil
+}
+
+// MustParseLossless is like ParseLossless, but panics on errors.
+func MustParseLossless(s string) Version {
+	v, err := ParseLossless(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
 // ParseAny tries to parse any version found in a string. It starts
 // parsing at the first decimal digit [0-9], and stops when it finds
 // an invalid character. It returns an error only if there are no
@@ -100,161 +126,123 @@ func ParseAny(s string) (Version, error) {
 	return v, nil
 }
 
-func parse(s string) (Version, []error) {
-	var parsedMinor, parsedPatch, parsedPre, parsedMeta bool
+// formats are the DefaultFormats recorded by parse, indexed by the number of
+// major, minor and patch components parsed, less one, and whether
+// prerelease and metadata fields were parsed.
+var formats = [3][2][2]string{
+	{{Major, Major + Meta}, {Major + Pre, Major + Pre + Meta}},
+	{{MajorMinor, MajorMinor + Meta}, {MajorMinor + Pre, MajorMinor + Pre + Meta}},
+	{{MajorMinorPatch, MajorMinorPatch + Meta}, {MMPPre, Complete}},
+}
+
+// componentNames are the names of the major, minor and patch components, as
+// used in errors, indexed by mode.
+var componentNames = [...]string{modeMajor: "major", modeMinor: "minor", modePatch: "patch"}
+
+// parse parses s in a single pass. Where s is not valid semver 2.0.0, it
+// returns as much of the version as it parsed, along with the first error
+// found. Unless exact is true, LeadingZero and VersionIncomplete errors are
+// ignored. The fields of the version are substrings of s, so parse does not
+// allocate when s is a string, unless s is invalid or has a component too
+// large for an int.
+func parse[T string | []byte](s T, exact bool) (Version, error) {
 	var (
-		major = &bytes.Buffer{}
-		minor = &bytes.Buffer{}
-		patch = &bytes.Buffer{}
-		pre   = &bytes.Buffer{}
-		meta  = &bytes.Buffer{}
+		m      = modeMajor
+		parsed [modeMeta + 1]bool
+		start  [modeMeta + 1]int
+		end    [modeMeta + 1]int
+		err    error
+		i      int
 	)
-	targets := map[mode]*bytes.Buffer{
-		modeMajor: major,
-		modeMinor: minor,
-		modePatch: patch,
-		modePre:   pre,
-		modeMeta:  meta,
+	parsed[modeMajor] = true
+	enter := func(next mode) {
+		end[m], m = i, next
+		start[m] = i + 1
 	}
-	m := modeMajor
-	var i int
-	var c rune
-	// finalise takes the current buffers and tries to return a partial version
-	finalise := func(knownErrors ...error) (Version, []error) {
-		var err error
-		v := Version{}
-		v.DefaultFormat = Major
-		majorString := major.String()
-		if err := validateMMPFormat(majorString, "major"); err != nil {
-			knownErrors = append(knownErrors, err)
-		}
-		if v.Major, err = strconv.Atoi(majorString); err != nil {
-			return v, append(knownErrors, err)
-		}
-		if parsedMinor {
-			v.DefaultFormat = MajorMinor
-			minorString := minor.String()
-			if err := validateMMPFormat(minorString, "minor"); err != nil {
-				knownErrors = append(knownErrors, err)
-			}
-			if v.Minor, err = strconv.Atoi(minorString); err != nil {
-				return v, append(knownErrors, err)
-			}
-		}
-		if parsedPatch {
-			v.DefaultFormat = MajorMinorPatch
-			patchString := patch.String()
-			if err := validateMMPFormat(patchString, "patch"); err != nil {
-				knownErrors = append(knownErrors, err)
-			}
-			if v.Patch, err = strconv.Atoi(patchString); err != nil {
-				return v, append(knownErrors, err)
-			}
-
-		}
-		if parsedPre {
-			v.DefaultFormat = v.DefaultFormat + "-?"
+scan:
+	for ; i < len(s); i++ {
+		parsed[m] = true
+		c := s[i]
+		switch {
+		case c == '.' && (m == modeMajor || m == modeMinor):
+			enter(m + 1)
+			continue
+		case c == '-' && m < modePre:
+			enter(modePre)
+			continue
+		case c == '+' && m < modeMeta:
+			enter(modeMeta)
+			continue
 		}
-		if parsedMeta {
-			v.DefaultFormat = v.DefaultFormat + "+?"
+		if m <= modePatch && !isDigit(c) || m > modePatch && !isPreOrMetaChar(c) {
+			err = unexpectedCharacter(s, i)
+			break scan
 		}
-		v.Pre = pre.String()
-		v.Meta = meta.String()
-		return v, knownErrors
 	}
-	changeMode := func() (bool, error) {
-		if (m == modePre || m == modeMeta) && c == '-' {
-			return false, nil
+	end[m] = i
+	if err == nil && !parsed[modeMinor] {
+		if exact {
+			err = VersionIncomplete{"minor"}
 		}
-		if m == modeMeta && c == '+' {
-			return false, UnexpectedCharacter{c, i}
+	} else if err == nil && !parsed[modePatch] {
+		if exact {
+			err = VersionIncomplete{"patch"}
 		}
-		if m == modePatch && c == '.' {
-			return false, UnexpectedCharacter{c, i}
+	}
+
+	v := Version{DefaultFormat: Major}
+	for c := modeMajor; c <= modePatch && parsed[c]; c++ {
+		digits := s[start[c]:end[c]]
+		v.DefaultFormat = formats[c][0][0]
+		if len(digits) == 0 {
+			if err == nil {
+				err = ZeroLengthNumeric{componentNames[c]}
+			}
+			return v, err
 		}
-		if (m == modeMajor || m == modeMinor) && c == '.' {
-			m++
-			return true, nil
+		if len(digits) > 1 && digits[0] == '0' && exact && err == nil {
+			err = LeadingZero{componentNames[c], string(digits)}
 		}
+		n, big := parseComponent(digits)
 		switch c {
+		case modeMajor:
+			v.Major, v.bigMajor = n, big
+		case modeMinor:
+			v.Minor, v.bigMinor = n, big
 		default:
-			return false, nil
-		case '-':
-			m = modePre
-		case '+':
-			m = modeMeta
+			v.Patch, v.bigPatch = n, big
 		}
-		return true, nil
 	}
-	for i, c = range s {
-		if m == modeMinor {
-			parsedMinor = true
-		}
-		if m == modePatch {
-			parsedPatch = true
-		}
-		if m == modePre {
-			parsedPre = true
-		}
-		if m == modeMeta {
-			parsedMeta = true
-		}
-		switch c {
-		case '.', '-', '+':
-			changed, err := changeMode()
-			if err != nil {
-				return finalise(err)
-			}
-			if changed {
-				continue
-			}
-		}
-		switch m {
-		case modeMajor, modeMinor, modePatch:
-			if strings.ContainsRune(digits, c) {
-				targets[m].WriteRune(c)
-			} else {
-				return finalise(UnexpectedCharacter{c, i})
-			}
-		case modePre, modeMeta:
-			if strings.ContainsRune(validPreAndMetaChars, c) {
-				targets[m].WriteRune(c)
-			} else {
-				return finalise(UnexpectedCharacter{c, i})
-			}
-		}
+	precision := 0
+	if parsed[modePatch] {
+		precision = 2
+	} else if parsed[modeMinor] {
+		precision = 1
 	}
-	if !parsedMinor {
-		return finalise(VersionIncomplete{"minor"})
+	// Empty prerelease and metadata fields, as in "1.2.3-+x", are not
+	// recorded in the format, so that it survives a round trip via String.
+	pre, meta := 0, 0
+	if parsed[modePre] && end[modePre] > start[modePre] {
+		v.Pre, pre = string(s[start[modePre]:end[modePre]]), 1
 	}
-	if !parsedPatch {
-		return finalise(VersionIncomplete{"patch"})
+	if parsed[modeMeta] && end[modeMeta] > start[modeMeta] {
+		v.Meta, meta = string(s[start[modeMeta]:end[modeMeta]]), 1
 	}
-	return finalise(nil)
+	v.DefaultFormat = formats[precision][pre][meta]
+	return v, err
 }
 
-func replaceAll(s string, replacements map[string]interface{}) string {
-	for what, replacement := range replacements {
-		s = strings.Replace(s, what, fmt.Sprint(replacement), -1)
-	}
-	return s
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
 }
 
-func firstErr(errs ...error) error {
-	for _, err := range errs {
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+func isPreOrMetaChar(c byte) bool {
+	return isDigit(c) || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '.' || c == '-'
 }
 
-func validateMMPFormat(s, name string) error {
-	if len(s) == 0 {
-		return ZeroLengthNumeric{name}
-	}
-	if len(s) > 1 && s[0] == '0' {
-		return LeadingZero{name, s}
-	}
-	return nil
+// unexpectedCharacter returns an UnexpectedCharacter error for the character
+// at byte offset i of s.
+func unexpectedCharacter[T string | []byte](s T, i int) error {
+	c, _ := utf8.DecodeRuneInString(string(s[i:min(i+utf8.UTFMax, len(s))]))
+	return UnexpectedCharacter{c, i}
 }
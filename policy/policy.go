@@ -0,0 +1,389 @@
+/*
+Package policy enforces rules about which version changes and declared ranges
+are allowed, such as "services may only auto-upgrade within patch", or "no
+prereleases in prod".
+
+A Policy is plain data, so it can be written in JSON, and read using Read or
+Load, or in YAML, and read by passing a gopkg.in/yaml.v2 or yaml.v3 Decoder to
+Decode. For example:
+
+	rules:
+	  - name: auto-upgrade within patch
+	    packages: ["svc-*"]
+	    maxChange: patch
+	  - name: no prereleases in prod
+	    environments: [prod]
+	    noPrereleases: true
+	  - name: one major at a time
+	    maxMajorJump: 1
+	  - name: pin 0.x exactly
+	    pinZeroMajor: true
+*/
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path"
+	"reflect"
+	"slices"
+
+	"github.com/samsalisbury/semv"
+)
+
+type (
+	// Policy is a list of rules, all of which must be followed.
+	Policy struct {
+		Rules []Rule `json:"rules" yaml:"rules"`
+		// compiled holds the parsed fields of each of Rules, set by Validate.
+		compiled []compiledRule
+	}
+	// Rule restricts version transitions and range declarations. The zero
+	// value of each restriction imposes no restriction.
+	Rule struct {
+		// Name identifies the rule in violations.
+		Name string `json:"name" yaml:"name"`
+		// Packages are path.Match patterns, e.g. "svc-*". If any are given,
+		// the rule only applies to packages matching one of them.
+		Packages []string `json:"packages,omitempty" yaml:"packages,omitempty"`
+		// Environments, if any are given, are the only environments the rule
+		// applies to.
+		Environments []string `json:"environments,omitempty" yaml:"environments,omitempty"`
+		// MaxChange is the most significant change allowed, as named by
+		// semv.Change, e.g. "patch". For a range, this is the most
+		// significant change between any two versions which satisfy it, so
+		// "patch" allows "~1.2.3" but not "^1.2.3".
+		MaxChange string `json:"maxChange,omitempty" yaml:"maxChange,omitempty"`
+		// MaxMajorJump is the largest increase in major version allowed by a
+		// single transition, e.g. 1 forbids upgrading from 1.x to 3.x.
+		MaxMajorJump int `json:"maxMajorJump,omitempty" yaml:"maxMajorJump,omitempty"`
+		// NoDowngrades forbids transitions to lower versions.
+		NoDowngrades bool `json:"noDowngrades,omitempty" yaml:"noDowngrades,omitempty"`
+		// NoPrereleases forbids transitions to prereleases, and ranges with
+		// prerelease bounds, which opt in to prereleases.
+		NoPrereleases bool `json:"noPrereleases,omitempty" yaml:"noPrereleases,omitempty"`
+		// PinZeroMajor requires ranges satisfied by any 0.x version to be an
+		// exact version.
+		PinZeroMajor bool `json:"pinZeroMajor,omitempty" yaml:"pinZeroMajor,omitempty"`
+		// Versions is a range set, e.g. "^2.0.0 || ^3.0.0", which transitions
+		// must be to a version satisfying, and ranges must lie within one of
+		// the ranges of.
+		Versions string `json:"versions,omitempty" yaml:"versions,omitempty"`
+	}
+	// Subject identifies what a transition or range is for.
+	Subject struct {
+		Package, Environment string
+	}
+	// Violation is a breach of a rule.
+	Violation struct {
+		Rule, Reason string
+	}
+	// InvalidRule is an error returned by Validate when a rule has a
+	// malformed field.
+	InvalidRule struct {
+		Rule, Field string
+		Err         error
+	}
+	// Decoder decodes a single value, like json.Decoder, or the Decoders of
+	// gopkg.in/yaml.v2 and yaml.v3.
+	Decoder interface {
+		Decode(v interface{}) error
+	}
+	// compiledRule is a copy of a Rule with its fields parsed. If any field is
+	// malformed, err is the InvalidRule describing it.
+	compiledRule struct {
+		Rule
+		maxChange semv.Change
+		versions  semv.RangeSet
+		err       error
+	}
+)
+
+func (err InvalidRule) Error() string {
+	return fmt.Sprintf("rule %q: invalid %s: %s", err.Rule, err.Field, err.Err)
+}
+
+func (err InvalidRule) Unwrap() error {
+	return err.Err
+}
+
+func (v Violation) String() string {
+	return v.Rule + ": " + v.Reason
+}
+
+// Decode decodes a policy using dec, and validates it. Whether unknown fields
+// are rejected is up to dec; to reject them in YAML, as Read does in JSON,
+// call KnownFields(true) on a gopkg.in/yaml.v3 Decoder, or use UnmarshalStrict
+// with yaml.v2, then Validate.
+func Decode(dec Decoder) (Policy, error) {
+	var p Policy
+	if err := dec.Decode(&p); err != nil {
+		return Policy{}, err
+	}
+	if err := p.Validate(); err != nil {
+		return Policy{}, err
+	}
+	return p, nil
+}
+
+// Read reads a JSON policy from r, and validates it. Unknown fields are
+// errors, so that misspelt restrictions are not silently ignored.
+func Read(r io.Reader) (Policy, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	return Decode(dec)
+}
+
+// Load reads a JSON policy from the file at path, and validates it.
+func Load(path string) (Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Policy{}, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Validate returns an InvalidRule error for the first malformed field of
+// any rule. Otherwise, it parses the rules once, for use by the Check
+// methods. Policies returned by Decode, Read and Load are already validated.
+//
+// The Check methods of a policy which has not been validated, or whose rules
+// have changed since, parse its rules on every call, and report every
+// malformed rule as a violation.
+func (p *Policy) Validate() error {
+	compiled := make([]compiledRule, len(p.Rules))
+	for i, r := range p.Rules {
+		if compiled[i] = r.compile(); compiled[i].err != nil {
+			p.compiled = nil
+			return compiled[i].err
+		}
+	}
+	p.compiled = compiled
+	return nil
+}
+
+// rules returns the compiled rules, compiling them if the policy has not been
+// validated, or if its rules have changed since.
+func (p Policy) rules() []compiledRule {
+	if p.compiled != nil && len(p.compiled) == len(p.Rules) {
+		current := true
+		for i, c := range p.compiled {
+			if current = reflect.DeepEqual(c.Rule, p.Rules[i]); !current {
+				break
+			}
+		}
+		if current {
+			return p.compiled
+		}
+	}
+	compiled := make([]compiledRule, len(p.Rules))
+	for i, r := range p.Rules {
+		compiled[i] = r.compile()
+	}
+	return compiled
+}
+
+// compile parses the fields of r. The compiled rule holds its own copy of r,
+// so that changes to r can be detected.
+func (r Rule) compile() compiledRule {
+	r.Packages, r.Environments = slices.Clone(r.Packages), slices.Clone(r.Environments)
+	c := compiledRule{Rule: r}
+	invalid := func(field string, err error) compiledRule {
+		c.err = InvalidRule{r.Name, field, err}
+		return c
+	}
+	for _, pattern := range r.Packages {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return invalid("package pattern "+pattern, err)
+		}
+	}
+	var err error
+	if c.maxChange, err = parseChange(r.MaxChange); err != nil {
+		return invalid("maxChange", err)
+	}
+	if r.MaxMajorJump < 0 {
+		return invalid("maxMajorJump", fmt.Errorf("%d is negative", r.MaxMajorJump))
+	}
+	if r.Versions != "" {
+		if c.versions, err = semv.ParseRangeSet(r.Versions); err != nil {
+			return invalid("versions", err)
+		}
+	}
+	return c
+}
+
+// parseChange parses the name of a semv.Change, returning MajorChange, which
+// imposes no restriction, for the empty string.
+func parseChange(s string) (semv.Change, error) {
+	if s == "" {
+		return semv.MajorChange, nil
+	}
+	for c := semv.NoChange; c <= semv.MajorChange; c++ {
+		if c.String() == s {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown change %q", s)
+}
+
+// invalid returns the violation reported for a malformed rule, which applies
+// to every subject, since it is unknown what the rule was meant to allow.
+func (r compiledRule) invalid() Violation {
+	invalid := r.err.(InvalidRule)
+	return Violation{r.Name, fmt.Sprintf("invalid %s: %s", invalid.Field, invalid.Err)}
+}
+
+// appliesTo returns true if the rule applies to s.
+func (r Rule) appliesTo(s Subject) bool {
+	if len(r.Environments) != 0 && !contains(r.Environments, s.Environment) {
+		return false
+	}
+	if len(r.Packages) == 0 {
+		return true
+	}
+	for _, pattern := range r.Packages {
+		if ok, _ := path.Match(pattern, s.Package); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTransition returns the violations of rules applying to s caused by
+// changing from one version to another.
+func (p Policy) CheckTransition(s Subject, from, to semv.Version) []Violation {
+	var violations []Violation
+	d := semv.Diff(from, to)
+	for _, r := range p.rules() {
+		if r.err != nil {
+			violations = append(violations, r.invalid())
+			continue
+		}
+		if !r.appliesTo(s) {
+			continue
+		}
+		violate := func(format string, args ...interface{}) {
+			violations = append(violations, Violation{r.Name, fmt.Sprintf(format, args...)})
+		}
+		if d.Change > r.maxChange {
+			violate("%s, but only %s changes are allowed", d, r.maxChange)
+		}
+		if r.MaxMajorJump > 0 {
+			fromMajor, _, _ := from.Big()
+			toMajor, _, _ := to.Big()
+			if jump := toMajor.Sub(toMajor, fromMajor); jump.Cmp(big.NewInt(int64(r.MaxMajorJump))) > 0 {
+				violate("%s jumps %s major versions, but at most %d are allowed", d, jump, r.MaxMajorJump)
+			}
+		}
+		if r.NoDowngrades && d.Downgrade {
+			violate("%s is not allowed", d)
+		}
+		if r.NoPrereleases && to.IsPrerelease() {
+			violate("%s is a prerelease", to)
+		}
+		if r.versions != nil && !r.versions.SatisfiedBy(to) {
+			violate("%s does not satisfy %s", to, r.Versions)
+		}
+	}
+	return violations
+}
+
+// CheckRange returns the violations of rules applying to s caused by
+// declaring a dependency on a range.
+func (p Policy) CheckRange(s Subject, rng semv.Range) []Violation {
+	var violations []Violation
+	for _, r := range p.rules() {
+		if r.err != nil {
+			violations = append(violations, r.invalid())
+			continue
+		}
+		if !r.appliesTo(s) {
+			continue
+		}
+		violate := func(format string, args ...interface{}) {
+			violations = append(violations, Violation{r.Name, fmt.Sprintf(format, args...)})
+		}
+		if c := widestChange(rng); c > r.maxChange {
+			violate("%s allows %s changes, but only %s changes are allowed", rng, c, r.maxChange)
+		}
+		if r.NoPrereleases {
+			for _, bound := range []*semv.Version{rng.Min, rng.MinEqual, rng.Max, rng.MaxEqual} {
+				if bound != nil && bound.IsPrerelease() {
+					violate("%s opts in to prereleases with the bound %s", rng, bound)
+					break
+				}
+			}
+		}
+		if r.PinZeroMajor {
+			_, exact := rng.Exact()
+			if !exact && !rng.Intersect(semv.LessThan(semv.NewMajorMinorPatch(1, 0, 0))).IsEmpty() {
+				violate("%s allows 0.x versions, which must be pinned exactly", rng)
+			}
+		}
+		if r.versions != nil && !within(rng, r.versions) {
+			violate("%s is not within %s", rng, r.Versions)
+		}
+	}
+	return violations
+}
+
+// within returns true if every version satisfying rng satisfies one of the
+// ranges in rs.
+func within(rng semv.Range, rs semv.RangeSet) bool {
+	for _, r := range rs {
+		if rng.Intersect(r).Equals(rng) {
+			return true
+		}
+	}
+	return false
+}
+
+// widestChange returns the most significant change between any two versions
+// satisfying rng.
+func widestChange(rng semv.Range) semv.Change {
+	rng = rng.Normalize()
+	if _, ok := rng.Exact(); ok || rng.IsEmpty() {
+		return semv.NoChange
+	}
+	var lo semv.Version
+	if rng.MinEqual != nil {
+		lo = *rng.MinEqual
+	} else if rng.Min != nil {
+		lo = *rng.Min
+	}
+	switch {
+	case rng.MaxEqual != nil:
+		return semv.Diff(lo, *rng.MaxEqual).Change
+	case rng.Max == nil:
+		return semv.MajorChange
+	}
+	// Versions less than an exclusive upper bound such as 2.0.0 or 1.3.0
+	// never reach its major or minor version, respectively.
+	hi := *rng.Max
+	c := semv.Diff(lo, hi).Change
+	switch {
+	case c == semv.MajorChange && hi.Minor == 0 && hi.Patch == 0 && hi.Major == lo.Major+1:
+		return semv.MinorChange
+	case c == semv.MinorChange && hi.Patch == 0 && hi.Minor == lo.Minor+1:
+		return semv.PatchChange
+	case c == semv.PatchChange && hi.Patch == lo.Patch+1:
+		if lo.IsPrerelease() {
+			return semv.PrereleaseChange
+		}
+		return semv.MetadataChange
+	}
+	return c
+}
@@ -0,0 +1,237 @@
+package policy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/samsalisbury/semv"
+)
+
+const policyJSON = `{
+  "rules": [
+    {"name": "patch only", "packages": ["svc-*"], "maxChange": "patch"},
+    {"name": "stable prod", "environments": ["prod"], "noPrereleases": true},
+    {"name": "one major", "maxMajorJump": 1, "noDowngrades": true},
+    {"name": "pin 0.x", "pinZeroMajor": true},
+    {"name": "supported", "packages": ["lib-*"], "versions": "^2.0.0 || ^3.0.0"}
+  ]
+}`
+
+func mustRead(t *testing.T) Policy {
+	p, err := Read(strings.NewReader(policyJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func rules(vs []Violation) string {
+	names := make([]string, len(vs))
+	for i, v := range vs {
+		names[i] = v.Rule
+	}
+	return strings.Join(names, ", ")
+}
+
+func TestPolicy_CheckTransition(t *testing.T) {
+	p := mustRead(t)
+	tests := []struct {
+		pkg, env, from, to string
+		violated           string
+	}{
+		{"svc-a", "dev", "1.2.3", "1.2.4", ""},
+		{"svc-a", "dev", "1.2.3", "1.3.0", "patch only"},
+		{"svc-a", "prod", "1.2.3", "1.2.4-rc.1", "stable prod"},
+		{"svc-a", "dev", "1.2.3", "1.2.4-rc.1", ""},
+		{"other", "dev", "1.2.3", "2.0.0", ""},
+		{"other", "dev", "1.2.3", "3.0.0", "one major"},
+		{"other", "dev", "1.2.3", "1.2.2", "one major"},
+		{"svc-a", "prod", "2.0.0", "1.0.0-beta", "patch only, stable prod, one major"},
+		{"lib-a", "dev", "2.1.0", "3.0.0", ""},
+		{"lib-a", "dev", "3.1.0", "4.0.0", "supported"},
+		{"other", "dev", "1.0.0", "100000000000000000000.0.0", "one major"},
+	}
+	for _, test := range tests {
+		vs := p.CheckTransition(Subject{test.pkg, test.env}, semv.MustParse(test.from), semv.MustParse(test.to))
+		if actual := rules(vs); actual != test.violated {
+			t.Errorf("%s in %s from %s to %s: got violations of %q; want %q", test.pkg, test.env, test.from, test.to, actual, test.violated)
+		}
+	}
+}
+
+func TestPolicy_CheckRange(t *testing.T) {
+	p := mustRead(t)
+	tests := []struct {
+		pkg, env, rng string
+		violated      string
+	}{
+		{"svc-a", "dev", "~1.2.3", ""},
+		{"svc-a", "dev", "^1.2.3", "patch only"},
+		{"svc-a", "dev", "1.2.3", ""},
+		{"svc-a", "dev", ">=1.2.3 <=1.2.9", ""},
+		{"svc-a", "dev", ">=1.2.3", "patch only"},
+		{"svc-a", "prod", "~1.2.3-rc.1", "stable prod"},
+		{"svc-a", "dev", "~0.2.3", "pin 0.x"},
+		{"svc-a", "dev", "0.2.3", ""},
+		{"other", "dev", "<2.0.0", "pin 0.x"},
+		{"lib-a", "dev", "^2.1.0", ""},
+		{"lib-a", "dev", ">=2.1.0 <4.0.0", "supported"},
+		{"lib-a", "dev", "^4.0.0", "supported"},
+	}
+	for _, test := range tests {
+		vs := p.CheckRange(Subject{test.pkg, test.env}, semv.MustParseRange(test.rng))
+		if actual := rules(vs); actual != test.violated {
+			t.Errorf("%s in %s declaring %s: got violations of %q; want %q", test.pkg, test.env, test.rng, actual, test.violated)
+		}
+	}
+}
+
+func TestWidestChange(t *testing.T) {
+	tests := map[string]semv.Change{
+		"1.2.3":            semv.NoChange,
+		">2.0.0 <1.0.0":    semv.NoChange,
+		"~1.2.3":           semv.PatchChange,
+		"^1.2.3":           semv.MinorChange,
+		"^0.2.3":           semv.MinorChange,
+		"<1.0.0":           semv.MinorChange,
+		">=1.2.3 <1.2.4":   semv.MetadataChange,
+		">=1.2.3-a <1.2.4": semv.PrereleaseChange,
+		">=1.2.3 <=1.4.0":  semv.MinorChange,
+		">=1.2.3 <3.0.0":   semv.MajorChange,
+		">=1.2.3":          semv.MajorChange,
+	}
+	for rng, expected := range tests {
+		if actual := widestChange(semv.MustParseRange(rng)); actual != expected {
+			t.Errorf("widestChange(%s): got %s; want %s", rng, actual, expected)
+		}
+	}
+}
+
+func TestViolation_String(t *testing.T) {
+	p := mustRead(t)
+	vs := p.CheckTransition(Subject{"svc-a", "dev"}, semv.MustParse("1.2.3"), semv.MustParse("1.3.0"))
+	expected := "patch only: minor upgrade from 1.2.3 to 1.3.0, but only patch changes are allowed"
+	if len(vs) != 1 || vs[0].String() != expected {
+		t.Errorf("got %v; want [%s]", vs, expected)
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	tests := map[string]string{
+		`{"rules": [{"name": "a", "maxChange": "huge"}]}`:  "maxChange",
+		`{"rules": [{"name": "a", "maxMajorJump": -1}]}`:   "maxMajorJump",
+		`{"rules": [{"name": "a", "versions": "^x"}]}`:     "versions",
+		`{"rules": [{"name": "a", "packages": ["[a"]}]}`:   "package pattern [a",
+		`{"rules": [{"name": "a", "noPrerelease": true}]}`: "",
+	}
+	for input, field := range tests {
+		_, err := Read(strings.NewReader(input))
+		if err == nil {
+			t.Errorf("reading %s: got nil error", input)
+			continue
+		}
+		var invalid InvalidRule
+		if field == "" {
+			if errors.As(err, &invalid) {
+				t.Errorf("reading %s: got %v; want a decoding error", input, err)
+			}
+		} else if !errors.As(err, &invalid) || invalid.Field != field {
+			t.Errorf("reading %s: got %v; want an InvalidRule for %s", input, err, field)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(policyJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Rules) != 5 || p.Rules[4].Versions != "^2.0.0 || ^3.0.0" {
+		t.Errorf("got %+v", p)
+	}
+}
+
+// decoderFunc is a Decoder standing in for a YAML decoder.
+type decoderFunc func(v interface{}) error
+
+func (f decoderFunc) Decode(v interface{}) error {
+	return f(v)
+}
+
+func TestDecode(t *testing.T) {
+	rules := []Rule{{Name: "patch only", MaxChange: "patch"}, {Name: "supported", Versions: "^1.0.0"}}
+	p, err := Decode(decoderFunc(func(v interface{}) error {
+		v.(*Policy).Rules = rules
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.compiled) != 2 || p.compiled[0].maxChange != semv.PatchChange || len(p.compiled[1].versions) != 1 {
+		t.Errorf("got compiled rules %+v; want patch and ^1.0.0", p.compiled)
+	}
+	_, err = Decode(decoderFunc(func(v interface{}) error {
+		v.(*Policy).Rules = []Rule{{Name: "a", MaxChange: "huge"}}
+		return nil
+	}))
+	if invalid, ok := err.(InvalidRule); !ok || invalid.Field != "maxChange" {
+		t.Errorf("got %v; want an InvalidRule for maxChange", err)
+	}
+	decodeErr := errors.New("bad yaml")
+	if _, err := Decode(decoderFunc(func(interface{}) error { return decodeErr })); err != decodeErr {
+		t.Errorf("got %v; want %v", err, decodeErr)
+	}
+}
+
+func TestPolicy_Unvalidated(t *testing.T) {
+	p := Policy{Rules: []Rule{
+		{Name: "patch only", MaxChange: "patch"},
+		{Name: "typo", Packages: []string{"svc-*"}, MaxChange: "pach"},
+	}}
+	vs := p.CheckTransition(Subject{"other", "dev"}, semv.MustParse("1.2.3"), semv.MustParse("1.3.0"))
+	if actual := rules(vs); actual != "patch only, typo" {
+		t.Errorf("got violations of %q; want %q", actual, "patch only, typo")
+	}
+	expected := `typo: invalid maxChange: unknown change "pach"`
+	if len(vs) == 2 && vs[1].String() != expected {
+		t.Errorf("got %q; want %q", vs[1], expected)
+	}
+	if vs := p.CheckRange(Subject{"other", "dev"}, semv.MustParseRange("1.2.3")); rules(vs) != "typo" {
+		t.Errorf("got violations of %q; want %q", rules(vs), "typo")
+	}
+	if err := p.Validate(); err == nil || p.compiled != nil {
+		t.Errorf("got %v, compiled %v; want an error and no compiled rules", err, p.compiled)
+	}
+	p.Rules[1].MaxChange = "minor"
+	if err := p.Validate(); err != nil || len(p.compiled) != 2 {
+		t.Errorf("got %v, %d compiled rules; want nil, 2", err, len(p.compiled))
+	}
+}
+
+func TestPolicy_ChangedAfterRead(t *testing.T) {
+	p := mustRead(t)
+	from, to := semv.MustParse("1.2.3"), semv.MustParse("1.3.0")
+	p.Rules[0].MaxChange = "minor"
+	if vs := p.CheckTransition(Subject{"svc-a", "dev"}, from, to); len(vs) != 0 {
+		t.Errorf("got %v after allowing minor changes; want none", vs)
+	}
+	p.Rules[0].MaxChange = "patch"
+	p.Rules[0].Packages[0] = "other-*"
+	if vs := p.CheckTransition(Subject{"svc-a", "dev"}, from, to); len(vs) != 0 {
+		t.Errorf("got %v after changing the package pattern; want none", vs)
+	}
+	if vs := p.CheckTransition(Subject{"other-a", "dev"}, from, to); rules(vs) != "patch only" {
+		t.Errorf("got violations of %q; want %q", rules(vs), "patch only")
+	}
+	p.Rules[0].MaxChange = "huge"
+	if vs := p.CheckTransition(Subject{"svc-a", "dev"}, from, from); rules(vs) != "patch only" {
+		t.Errorf("got violations of %q after making the rule invalid; want %q", rules(vs), "patch only")
+	}
+}
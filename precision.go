@@ -0,0 +1,83 @@
+package semv
+
+import "strings"
+
+// Precision is the number of major, minor and patch components a version is
+// written with. Parse records the precision of the input, so that
+// Parse("1.2").String() == "1.2", and reports inputs with fewer than three
+// components via IsPartial. Precisions are ordered by their number of
+// components, so they can be compared, e.g. p < PrecisionFull, and the zero
+// Precision is PrecisionFull.
+type Precision int
+
+const (
+	// PrecisionMajor versions are written with only a major component,
+	// e.g. "1".
+	PrecisionMajor Precision = iota - 2
+	// PrecisionMajorMinor versions are written with major and minor
+	// components, e.g. "1.2".
+	PrecisionMajorMinor
+	// PrecisionFull versions are written with major, minor and patch
+	// components, e.g. "1.2.3". Versions which were not parsed, or whose
+	// DefaultFormat is not one of the format constants, have full precision.
+	PrecisionFull
+)
+
+// String returns "major", "major.minor" or "full".
+func (p Precision) String() string {
+	switch p {
+	case PrecisionMajor:
+		return "major"
+	case PrecisionMajorMinor:
+		return "major.minor"
+	}
+	return "full"
+}
+
+// format returns the format string for p, which prints the prerelease and
+// metadata fields if they are not empty.
+func (p Precision) format() string {
+	switch p {
+	case PrecisionMajor:
+		return Major + Pre + Meta
+	case PrecisionMajorMinor:
+		return MajorMinor + Pre + Meta
+	}
+	return Complete
+}
+
+// Precision returns the precision this version is written with by String,
+// according to its DefaultFormat.
+func (v Version) Precision() Precision {
+	switch f := v.DefaultFormat; {
+	case f == "" || strings.HasPrefix(f, MajorMinorPatch):
+		return PrecisionFull
+	case strings.HasPrefix(f, MajorMinor):
+		return PrecisionMajorMinor
+	case strings.HasPrefix(f, Major):
+		return PrecisionMajor
+	}
+	return PrecisionFull
+}
+
+// IsPartial returns true if this version is written with fewer than three
+// components, e.g. because it was parsed from "1" or "1.2". The missing
+// components are zero.
+func (v Version) IsPartial() bool {
+	return v.Precision() != PrecisionFull
+}
+
+// WithPrecision returns a copy of this version which String writes with
+// precision p, along with its prerelease and metadata, if they are not empty.
+// Reducing the precision does not change the minor or patch fields, so
+// String does not show them even if they are not zero.
+func (v Version) WithPrecision(p Precision) Version {
+	v.DefaultFormat = p.format()
+	return v
+}
+
+// Canonical returns this version in full semver 2.0.0 format, "M.m.p-?+?",
+// regardless of its precision or DefaultFormat.
+func (v Version) Canonical() string {
+	return v.Format(Complete)
+}
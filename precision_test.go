@@ -0,0 +1,93 @@
+package semv
+
+import "testing"
+
+var precisionTests = map[string]struct {
+	precision Precision
+	partial   bool
+}{
+	"1":            {PrecisionMajor, true},
+	"1-beta":       {PrecisionMajor, true},
+	"1.2":          {PrecisionMajorMinor, true},
+	"1.2+abc":      {PrecisionMajorMinor, true},
+	"1.2.3":        {PrecisionFull, false},
+	"1.2.3-rc.1+x": {PrecisionFull, false},
+}
+
+func TestVersion_Precision(t *testing.T) {
+	for input, expected := range precisionTests {
+		v := MustParse(input)
+		if p := v.Precision(); p != expected.precision {
+			t.Errorf("got %q.Precision() == %s; want %s", input, p, expected.precision)
+		}
+		if partial := v.IsPartial(); partial != expected.partial {
+			t.Errorf("got %q.IsPartial() == %t; want %t", input, partial, expected.partial)
+		}
+	}
+	if p := NewVersion(1, 2, 3, "", "").Precision(); p != PrecisionFull {
+		t.Errorf("got constructed version precision %s; want %s", p, PrecisionFull)
+	}
+}
+
+func TestVersion_WithPrecision(t *testing.T) {
+	tests := []struct {
+		input     string
+		precision Precision
+		expected  string
+	}{
+		{"1", PrecisionFull, "1.0.0"},
+		{"1.2-beta", PrecisionFull, "1.2.0-beta"},
+		{"1.2.3+abc", PrecisionMajorMinor, "1.2+abc"},
+		{"1.2.3-rc.1", PrecisionMajor, "1-rc.1"},
+		{"1.2.0", PrecisionMajorMinor, "1.2"},
+	}
+	for _, test := range tests {
+		v := MustParse(test.input).WithPrecision(test.precision)
+		if s := v.String(); s != test.expected {
+			t.Errorf("got %q.WithPrecision(%s) == %q; want %q", test.input, test.precision, s, test.expected)
+		}
+		if p := v.Precision(); p != test.precision {
+			t.Errorf("got precision %s after WithPrecision(%s)", p, test.precision)
+		}
+	}
+}
+
+func TestPrecision_Order(t *testing.T) {
+	if !(PrecisionMajor < PrecisionMajorMinor && PrecisionMajorMinor < PrecisionFull) {
+		t.Errorf("got precisions %d, %d, %d; want them ordered by number of components",
+			PrecisionMajor, PrecisionMajorMinor, PrecisionFull)
+	}
+	var zero Precision
+	if zero != PrecisionFull || !(PrecisionMajorMinor < zero) {
+		t.Errorf("got zero Precision %d; want PrecisionFull, %d", zero, PrecisionFull)
+	}
+}
+
+func TestVersion_Canonical(t *testing.T) {
+	tests := map[string]string{
+		"1":              "1.0.0",
+		"1.2+abc":        "1.2.0+abc",
+		"1.2.3-beta":     "1.2.3-beta",
+		"01.2.3-rc+meta": "1.2.3-rc+meta",
+	}
+	for input, expected := range tests {
+		if actual := MustParse(input).Canonical(); actual != expected {
+			t.Errorf("got %q.Canonical() == %q; want %q", input, actual, expected)
+		}
+	}
+	v := MustParseLossless("v1.2")
+	if actual := v.Canonical(); actual != "1.2.0" {
+		t.Errorf("got %q.Canonical() == %q; want %q", v, actual, "1.2.0")
+	}
+}
+
+func TestVersion_SetFormat(t *testing.T) {
+	v := MustParse("1.2.3-beta")
+	v.SetFormat(MajorMinor)
+	if s := v.String(); s != "1.2" {
+		t.Errorf("got %q after SetFormat(%q); want %q", s, MajorMinor, "1.2")
+	}
+	if p := v.Precision(); p != PrecisionMajorMinor {
+		t.Errorf("got precision %s after SetFormat(%q); want %s", p, MajorMinor, PrecisionMajorMinor)
+	}
+}
@@ -0,0 +1,132 @@
+package semv
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// quickVersion generates versions for testing/quick, drawing each component
+// from a small alphabet so that equal and nearly equal versions are common.
+type quickVersion struct{ Version }
+
+var (
+	quickComponents  = []string{"0", "1", "2", "10", "99999999999999999999", "100000000000000000000"}
+	quickIdentifiers = []string{"0", "1", "2", "10", "01", "a", "b", "alpha", "x-y", "-", "1a"}
+)
+
+func (quickVersion) Generate(r *rand.Rand, size int) reflect.Value {
+	pick := func(s []string) string { return s[r.Intn(len(s))] }
+	s := pick(quickComponents) + "." + pick(quickComponents) + "." + pick(quickComponents)
+	if n := r.Intn(4); n != 0 {
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = pick(quickIdentifiers)
+		}
+		s += "-" + strings.Join(ids, ".")
+	}
+	if r.Intn(4) == 0 {
+		s += "+" + pick(quickIdentifiers)
+	}
+	return reflect.ValueOf(quickVersion{MustParse(s)})
+}
+
+var quickConfig = &quick.Config{MaxCount: 5000}
+
+// checkOrder fails the test unless Less is a strict weak order over a, b and
+// c, and Equals agrees with it.
+func checkOrder(t *testing.T, a, b, c Version) {
+	t.Helper()
+	if a.Less(a) {
+		t.Fatalf("%q is less than itself", a)
+	}
+	if a.Less(b) && b.Less(a) {
+		t.Fatalf("%q and %q are each less than the other", a, b)
+	}
+	if a.Less(b) && b.Less(c) && !a.Less(c) {
+		t.Fatalf("%q < %q < %q, but not %q < %q", a, b, c, a, c)
+	}
+	if equal := !a.Less(b) && !b.Less(a); a.Equals(b) != equal {
+		t.Fatalf("got %q.Equals(%q) == %t; Less says %t", a, b, a.Equals(b), equal)
+	}
+	if a.Equals(b) && b.Equals(c) && !a.Equals(c) {
+		t.Fatalf("%q == %q == %q, but not %q == %q", a, b, c, a, c)
+	}
+	if a.Equals(b) && (a.Less(c) != b.Less(c) || c.Less(a) != c.Less(b)) {
+		t.Fatalf("%q and %q are equal, but compare differently to %q", a, b, c)
+	}
+}
+
+func TestProperty_StrictWeakOrder(t *testing.T) {
+	f := func(a, b, c quickVersion) bool {
+		checkOrder(t, a.Version, b.Version, c.Version)
+		return true
+	}
+	if err := quick.Check(f, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProperty_StringRoundTrip(t *testing.T) {
+	f := func(v quickVersion) bool {
+		again, err := Parse(v.String())
+		return err == nil && again == v.Version
+	}
+	if err := quick.Check(f, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProperty_RangeStringRoundTrip(t *testing.T) {
+	constructors := []func(Version) Range{GreaterThan, LessThan, EqualTo, GreaterThanOrEqualTo, LessThanOrEqualTo}
+	f := func(a, b, v quickVersion, i, j uint8) bool {
+		r := constructors[int(i)%len(constructors)](a.Version).Intersect(constructors[int(j)%len(constructors)](b.Version))
+		again, err := ParseRange(r.String())
+		if err != nil {
+			t.Logf("%q does not parse: %s", r, err)
+			return false
+		}
+		return again.Equals(r) && again.SatisfiedBy(v.Version) == r.SatisfiedBy(v.Version)
+	}
+	if err := quick.Check(f, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestProperty_SatisfiedByMonotone checks that loosening a bound never
+// excludes a version which the tighter bound included. Prerelease versions
+// are excluded from ranges whose bounds do not share their major, minor and
+// patch, so only stable versions are checked.
+func TestProperty_SatisfiedByMonotone(t *testing.T) {
+	f := func(a, b, c quickVersion) bool {
+		lo, hi, v := a.Version, b.Version, c.MajorMinorPatch()
+		if hi.Less(lo) {
+			lo, hi = hi, lo
+		}
+		implies := func(p, q bool) bool { return !p || q }
+		return implies(GreaterThanOrEqualTo(hi).SatisfiedBy(v), GreaterThanOrEqualTo(lo).SatisfiedBy(v)) &&
+			implies(GreaterThan(hi).SatisfiedBy(v), GreaterThan(lo).SatisfiedBy(v)) &&
+			implies(LessThanOrEqualTo(lo).SatisfiedBy(v), LessThanOrEqualTo(hi).SatisfiedBy(v)) &&
+			implies(LessThan(lo).SatisfiedBy(v), LessThan(hi).SatisfiedBy(v))
+	}
+	if err := quick.Check(f, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLess_AlphanumericAfterNumeric(t *testing.T) {
+	pairs := [][2]string{
+		{"1.0.0-1", "1.0.0-x"},
+		{"1.0.0-1", "1.0.0--"},
+		{"1.0.0-99999999999999999999", "1.0.0-1a"},
+		{"1.0.0-a.1", "1.0.0-a.-"},
+	}
+	for _, pair := range pairs {
+		lo, hi := MustParse(pair[0]), MustParse(pair[1])
+		if !lo.Less(hi) || hi.Less(lo) {
+			t.Errorf("got %s.Less(%s) == %t and %s.Less(%s) == %t; want true and false", lo, hi, lo.Less(hi), hi, lo, hi.Less(lo))
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package semv
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type (
 	// Range is a semver range.
@@ -13,39 +16,95 @@ type (
 // allows the caret ^ and tilde ~ prefixes, as used by NPM, and also
 // >, >=, <, <= as prefixes to indicate greater than, greater than or
 // equal to, less than, and less than or equal to, respectively.
+//
+// Multiple comparators separated by whitespace must all be satisfied, e.g.
+// ">=1.0.0 <1.5.0", and hyphen ranges like "1.0.0 - 1.5.0" are inclusive
+// at both ends. The wildcard "*" is satisfied by any version.
 func ParseRange(s string) (Range, error) {
-	if len(s) == 0 {
+	fields := SplitComparators(s)
+	if len(fields) == 0 {
 		return Range{}, fmt.Errorf("cannot parse range from empty string")
 	}
-	v, err := ParseAny(s)
-	if err != nil {
-		return Range{}, err
+	if len(fields) == 3 && fields[1] == "-" {
+		min, err := Parse(fields[0])
+		if err != nil {
+			return Range{}, err
+		}
+		max, err := Parse(fields[2])
+		if err != nil {
+			return Range{}, err
+		}
+		return Range{MinEqual: &min, MaxEqual: &max}, nil
+	}
+	r := Range{}
+	for _, f := range fields {
+		c, err := parseComparator(f)
+		if err != nil {
+			return Range{}, err
+		}
+		r = r.Intersect(c)
+	}
+	return r, nil
+}
+
+// SplitComparators splits a range into the fields ParseRange parses, on
+// whitespace, joining any operator that is separated from its version by
+// whitespace back onto that version. For example, ">= 1.0.0 <2.0.0" is split
+// into ">=1.0.0" and "<2.0.0". The "-" of a hyphen range is its own field.
+func SplitComparators(s string) []string {
+	var fields []string
+	pending := ""
+	for _, f := range strings.Fields(s) {
+		if strings.Trim(f, "=<>~^") == "" && f != "-" {
+			pending += f
+			continue
+		}
+		fields = append(fields, pending+f)
+		pending = ""
+	}
+	if pending != "" {
+		fields = append(fields, pending)
 	}
-	if len(s) >= 2 {
-		switch s[:2] {
-		case "==":
-			return EqualTo(v), nil
-		case ">=":
-			return GreaterThanOrEqualTo(v), nil
-		case "<=":
-			return LessThanOrEqualTo(v), nil
+	return fields
+}
+
+// comparatorOperators are the operators a comparator may begin with, longest
+// first, so that e.g. ">=" is not taken for ">".
+var comparatorOperators = []string{"==", ">=", "<=", "=", ">", "<", "~", "^"}
+
+// parseComparator parses a single comparator, e.g. ">=1.0.0" or "^1.2". The
+// whole of the version after the operator must parse, although a leading v is
+// permitted, so ">=1.0.0foo" is an error.
+func parseComparator(s string) (Range, error) {
+	if s == "*" || s == "x" || s == "X" {
+		return Range{}, nil
+	}
+	op := ""
+	for _, o := range comparatorOperators {
+		if strings.HasPrefix(s, o) {
+			op = o
+			break
 		}
 	}
-	switch s[0] {
-	case '=', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+	v, err := Parse(strings.TrimPrefix(strings.TrimPrefix(s[len(op):], "v"), "V"))
+	if err != nil {
+		return Range{}, fmt.Errorf("unable to parse version range %q: %s", s, err)
+	}
+	switch op {
+	case "", "=", "==":
 		return EqualTo(v), nil
-	case '>':
+	case ">=":
+		return GreaterThanOrEqualTo(v), nil
+	case "<=":
+		return LessThanOrEqualTo(v), nil
+	case ">":
 		return GreaterThan(v), nil
-	case '<':
+	case "<":
 		return LessThan(v), nil
-	case '~':
-		max := v.IncrementMinor()
-		return GreaterThanOrEqualToAndLessThan(v, max), nil
-	case '^':
-		max := v.IncrementMajor()
-		return GreaterThanOrEqualToAndLessThan(v, max), nil
+	case "~":
+		return GreaterThanOrEqualToAndLessThan(v, v.IncrementMinor()), nil
 	}
-	return Range{}, fmt.Errorf("unable to parse version range %q", s)
+	return GreaterThanOrEqualToAndLessThan(v, v.IncrementMajor()), nil
 }
 
 // MustParseRange is similar to ParseRange except that it panics instead
@@ -134,49 +193,234 @@ func (r Range) SatisfiedBy(v Version) bool {
 	return true
 }
 
+// Normalize returns the canonical form of this range, which has at most one
+// lower and one upper bound. Where both an inclusive and an exclusive bound are
+// set on the same side, only the tighter one is kept. A range whose bounds are
+// both inclusive and equal is returned in the same form as EqualTo, and a range
+// which no version can satisfy is returned as Empty().
+func (r Range) Normalize() Range {
+	out := Range{}.Intersect(r)
+	if out.IsEmpty() {
+		return Empty()
+	}
+	if out.MinEqual != nil && out.MaxEqual != nil && out.MinEqual.Equals(*out.MaxEqual) {
+		out.MaxEqual = out.MinEqual
+	}
+	return out
+}
+
+// Exact returns the only version satisfying this range, if it is an exact
+// pin such as "=1.2.3". Otherwise, the second return value is false.
+func (r Range) Exact() (Version, bool) {
+	r = r.Normalize()
+	if r.MinEqual != nil && r.MinEqual == r.MaxEqual {
+		return *r.MinEqual, true
+	}
+	return Version{}, false
+}
+
+// Empty returns a range that is not satisfied by any version. It is the
+// canonical form returned by Normalize for unsatisfiable ranges.
+func Empty() Range {
+	return LessThan(NewMajorMinorPatch(0, 0, 0))
+}
+
+// IsEmpty returns true if the bounds of this range exclude every version,
+// e.g. ">2.0.0 <1.0.0", "<0.0.0", or ">1.0.0 <1.0.1", which lies between two
+// adjacent stable versions and does not opt in to prereleases.
+func (r Range) IsEmpty() bool {
+	min, minInclusive := r.lower()
+	max, maxInclusive := r.upper()
+	if max != nil && !maxInclusive && !max.IsPrerelease() && max.MMPEqual(NewMajorMinorPatch(0, 0, 0)) {
+		return true
+	}
+	if max == nil {
+		return false
+	}
+	if min == nil && max.IsPrerelease() {
+		return false
+	}
+	if min != nil && (min.IsPrerelease() || max.IsPrerelease()) {
+		if max.Less(*min) {
+			return true
+		}
+		return min.Equals(*max) && !(minInclusive && maxInclusive)
+	}
+	// Neither bound is a prerelease, so only stable versions satisfy the
+	// range, the lowest of which is the lowest stable version above min.
+	lowest := NewMajorMinorPatch(0, 0, 0)
+	if min != nil {
+		lowest = min.MajorMinorPatch()
+		if !minInclusive {
+			lowest = lowest.IncrementPatch()
+		}
+	}
+	return max.Less(lowest) || !maxInclusive && !lowest.Less(*max)
+}
+
+// Contains returns true if the version passed in lies within the bounds of
+// this range, according to semver 2.0.0 precedence alone. Unlike SatisfiedBy,
+// Contains does not exclude prerelease versions whose major, minor and patch
+// differ from those of the bounds, so for example ">=1.0.0 <2.0.0" contains
+// "1.5.0-beta" but is not satisfied by it. This is the behaviour expected when
+// describing which versions are affected by a bug, rather than which versions
+// may be selected.
+func (r Range) Contains(v Version) bool {
+	if min, inclusive := r.lower(); min != nil {
+		if v.Less(*min) || (!inclusive && !min.Less(v)) {
+			return false
+		}
+	}
+	if max, inclusive := r.upper(); max != nil {
+		if max.Less(v) || (!inclusive && !v.Less(*max)) {
+			return false
+		}
+	}
+	return true
+}
+
 // String returns the minimal string representation of this range. For example,
-// the range ">=1.0.0 <2.0.0" is compressed to "^1.0.0"
+// the range ">=1.0.0 <2.0.0" is compressed to "^1.0.0". The range is normalized
+// first, so equal ranges print the same way. A range satisfied only by a single
+// version is printed as "=1.0.0", and a range with no bounds is printed as "*".
+// Bounds are always printed in full, as by Canonical, since a partial format
+// such as that of the upper bound 1.1.0 of "~1" would hide components.
 func (r Range) String() string {
-	// Special case for exact equality range
-	if r.MinEqual != nil && r.MaxEqual != nil && r.MaxEqual.Equals(*r.MinEqual) {
-		return r.MinEqual.String()
+	r = r.Normalize()
+	if r.MinEqual != nil && r.MaxEqual != nil && r.MinEqual == r.MaxEqual {
+		return "=" + r.MinEqual.Canonical()
 	}
 	// Special case for tilde and caret ranges
 	if r.MinEqual != nil && r.Max != nil {
 		if r.Max.Equals(r.MinEqual.IncrementMajor()) {
-			return "^" + r.MinEqual.String()
+			return "^" + r.MinEqual.Canonical()
 		}
 		if r.Max.Equals(r.MinEqual.IncrementMinor()) {
-			return "~" + r.MinEqual.String()
+			return "~" + r.MinEqual.Canonical()
 		}
 	}
 	// All other cases
 	out := ""
 	if r.Min != nil {
-		out = ">" + r.Min.String()
+		out = ">" + r.Min.Canonical()
 	} else if r.MinEqual != nil {
-		out = ">=" + r.MinEqual.String()
+		out = ">=" + r.MinEqual.Canonical()
 	}
 	if r.Max != nil {
 		if out != "" {
 			out += " "
 		}
-		out += "<" + r.Max.String()
+		out += "<" + r.Max.Canonical()
 	} else if r.MaxEqual != nil {
 		if out != "" {
 			out += " "
 		}
-		out += "<=" + r.MaxEqual.String()
+		out += "<=" + r.MaxEqual.Canonical()
+	}
+	if out == "" {
+		return "*"
 	}
 	return out
 }
 
 // Equals returns true if the range passed in is semantically equivalent to the
 // range it is invoked on. (That is, if the same set of versions satisfies each
-// range.)
+// range.) Both ranges are normalized before their bounds are compared, so for
+// example ">=1.0.0 <=1.0.0" equals "=1.0.0", and all empty ranges are equal.
+//
+// Equals considers every possible version. To compare ranges only in terms of
+// a known set of versions, use EquivalentIn.
 func (r Range) Equals(other Range) bool {
+	r, other = r.Normalize(), other.Normalize()
 	return r.Min.ValueEquals(other.Min) &&
 		r.Max.ValueEquals(other.Max) &&
 		r.MinEqual.ValueEquals(other.MinEqual) &&
 		r.MaxEqual.ValueEquals(other.MaxEqual)
 }
+
+// EquivalentIn returns true if exactly the same versions in vl satisfy both
+// the range it is invoked on and the range passed in. For example, ">0.9.9"
+// and ">=1.0.0" are not Equal, but they are equivalent in any list containing
+// no versions between 0.9.9 and 1.0.0.
+func (r Range) EquivalentIn(other Range, vl VersionList) bool {
+	for _, v := range vl {
+		if r.SatisfiedBy(v) != other.SatisfiedBy(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Intersect returns a range satisfied only by versions which satisfy both the
+// range it is invoked on, and the range passed in. The result may be a range
+// which no version satisfies, e.g. the intersection of "<1.0.0" and ">2.0.0".
+func (r Range) Intersect(other Range) Range {
+	var out Range
+	if min, inclusive := tighterLower(r, other); inclusive {
+		out.MinEqual = min
+	} else {
+		out.Min = min
+	}
+	if max, inclusive := tighterUpper(r, other); inclusive {
+		out.MaxEqual = max
+	} else {
+		out.Max = max
+	}
+	return out
+}
+
+// lower returns the effective lower bound of this range, and whether it is
+// inclusive. If there is no lower bound, the returned version is nil.
+func (r Range) lower() (*Version, bool) {
+	switch {
+	case r.Min == nil:
+		return r.MinEqual, true
+	case r.MinEqual == nil, !r.Min.Less(*r.MinEqual):
+		return r.Min, false
+	}
+	return r.MinEqual, true
+}
+
+// upper returns the effective upper bound of this range, and whether it is
+// inclusive. If there is no upper bound, the returned version is nil.
+func (r Range) upper() (*Version, bool) {
+	switch {
+	case r.Max == nil:
+		return r.MaxEqual, true
+	case r.MaxEqual == nil, !r.MaxEqual.Less(*r.Max):
+		return r.Max, false
+	}
+	return r.MaxEqual, true
+}
+
+// tighterLower returns the greater of the lower bounds of a and b. Where
+// they are equal, an exclusive bound is tighter than an inclusive one.
+func tighterLower(a, b Range) (*Version, bool) {
+	av, aInc := a.lower()
+	bv, bInc := b.lower()
+	switch {
+	case av == nil:
+		return bv, bInc
+	case bv == nil, bv.Less(*av):
+		return av, aInc
+	case av.Less(*bv):
+		return bv, bInc
+	}
+	return av, aInc && bInc
+}
+
+// tighterUpper returns the lesser of the upper bounds of a and b. Where
+// they are equal, an exclusive bound is tighter than an inclusive one.
+func tighterUpper(a, b Range) (*Version, bool) {
+	av, aInc := a.upper()
+	bv, bInc := b.upper()
+	switch {
+	case av == nil:
+		return bv, bInc
+	case bv == nil, av.Less(*bv):
+		return av, aInc
+	case bv.Less(*av):
+		return bv, bInc
+	}
+	return av, aInc && bInc
+}
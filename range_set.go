@@ -0,0 +1,173 @@
+package semv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RangeSet is a union of Ranges. It is satisfied by any version which
+// satisfies at least one of its ranges. An empty RangeSet is satisfied by no
+// versions.
+type RangeSet []Range
+
+// ParseRangeSet parses a union of ranges separated by "||", each of which is
+// parsed using ParseRange. E.g. "^1.2.0 || >=3.0.0 <3.5.0".
+func ParseRangeSet(s string) (RangeSet, error) {
+	parts := strings.Split(s, "||")
+	rs := make(RangeSet, len(parts))
+	for i, part := range parts {
+		r, err := ParseRange(part)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %q: %s", s, err)
+		}
+		rs[i] = r
+	}
+	return rs, nil
+}
+
+// MustParseRangeSet is similar to ParseRangeSet except that it panics instead
+// of returning an error.
+func MustParseRangeSet(s string) RangeSet {
+	rs, err := ParseRangeSet(s)
+	if err != nil {
+		panic(err)
+	}
+	return rs
+}
+
+// SatisfiedBy returns true if the version passed in satisfies any of the
+// ranges in this set.
+func (rs RangeSet) SatisfiedBy(v Version) bool {
+	for _, r := range rs {
+		if r.SatisfiedBy(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains returns true if the version passed in is contained by any of the
+// ranges in this set. See Range.Contains.
+func (rs RangeSet) Contains(v Version) bool {
+	for _, r := range rs {
+		if r.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Intersect returns a RangeSet satisfied only by versions which satisfy both
+// the set it is invoked on and the set passed in.
+func (rs RangeSet) Intersect(other RangeSet) RangeSet {
+	out := make(RangeSet, 0, len(rs)*len(other))
+	for _, a := range rs {
+		for _, b := range other {
+			out = append(out, a.Intersect(b))
+		}
+	}
+	return out
+}
+
+// Normalize returns a copy of this set with each range normalized, and with
+// empty ranges, and ranges wholly contained by another range in the set,
+// removed. If no ranges remain, the set returned contains only Empty().
+func (rs RangeSet) Normalize() RangeSet {
+	normalized := make(RangeSet, 0, len(rs))
+	for _, r := range rs {
+		if r = r.Normalize(); !r.IsEmpty() {
+			normalized = append(normalized, r)
+		}
+	}
+	out := make(RangeSet, 0, len(normalized))
+	for i, r := range normalized {
+		if !normalized.subsumes(i) {
+			out = append(out, r)
+		}
+	}
+	if len(out) == 0 {
+		return RangeSet{Empty()}
+	}
+	return out
+}
+
+// subsumes returns true if the range at index i is contained by some other
+// range in the set. Of two equal ranges, only the later one is subsumed.
+func (rs RangeSet) subsumes(i int) bool {
+	for j, other := range rs {
+		if i == j || !rs[i].Intersect(other).Equals(rs[i]) {
+			continue
+		}
+		if j < i || !other.Equals(rs[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the ranges in this set separated by " || ".
+func (rs RangeSet) String() string {
+	parts := make([]string, len(rs))
+	for i, r := range rs {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, " || ")
+}
+
+// Simplify returns the shortest string representation of this set which
+// selects exactly the same versions from vl, in the same way as npm's
+// simplifyRange. Contiguous runs of satisfying versions in vl are described
+// using the fewest bounds possible, and if that result is not shorter than
+// String, then String is returned instead.
+func (rs RangeSet) Simplify(vl VersionList) string {
+	original := rs.String()
+	sorted := vl.Sorted()
+	var simplified RangeSet
+	start := -1
+	for i, v := range sorted {
+		if rs.SatisfiedBy(v) {
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+		if start != -1 {
+			simplified = append(simplified, spanRange(sorted, start, i-1))
+			start = -1
+		}
+	}
+	if start != -1 {
+		simplified = append(simplified, spanRange(sorted, start, len(sorted)-1))
+	}
+	if len(simplified) == 0 {
+		simplified = RangeSet{Empty()}
+	}
+	s := simplified.String()
+	if len(s) >= len(original) {
+		return original
+	}
+	for _, v := range sorted {
+		if simplified.SatisfiedBy(v) != rs.SatisfiedBy(v) {
+			return original
+		}
+	}
+	return s
+}
+
+// spanRange returns the range covering sorted[start] to sorted[end]
+// inclusive, omitting bounds which are not needed to exclude any other
+// versions in sorted.
+func spanRange(sorted VersionList, start, end int) Range {
+	min, max := sorted[start], sorted[end]
+	switch {
+	case min.Equals(max):
+		return EqualTo(min)
+	case start == 0 && end == len(sorted)-1:
+		return Range{}
+	case end == len(sorted)-1:
+		return GreaterThanOrEqualTo(min)
+	case start == 0:
+		return LessThanOrEqualTo(max)
+	}
+	return Range{MinEqual: &min, MaxEqual: &max}
+}
@@ -0,0 +1,91 @@
+package semv
+
+import "testing"
+
+func TestRangeSetSatisfiedBy(t *testing.T) {
+	rs := RangeSet{MustParseRange("~1.2.0"), MustParseRange("^3.0.0")}
+	for _, vs := range []string{"1.2.0", "1.2.9", "3.0.0", "3.9.0"} {
+		if !rs.SatisfiedBy(MustParse(vs)) {
+			t.Errorf("expected %q to be satisfied by %q", rs, vs)
+		}
+	}
+	for _, vs := range []string{"1.1.0", "1.3.0", "2.0.0", "4.0.0"} {
+		if rs.SatisfiedBy(MustParse(vs)) {
+			t.Errorf("expected %q not to be satisfied by %q", rs, vs)
+		}
+	}
+	if (RangeSet{}).SatisfiedBy(v1_0_0) {
+		t.Errorf("expected empty RangeSet not to be satisfied")
+	}
+}
+
+func TestRangeSetIntersect(t *testing.T) {
+	rs := RangeSet{MustParseRange("^1.0.0"), MustParseRange("^3.0.0")}.
+		Intersect(RangeSet{MustParseRange(">=1.5.0")})
+	expected := "^1.5.0 || ^3.0.0"
+	if actual := rs.String(); actual != expected {
+		t.Errorf("got %q; want %q", actual, expected)
+	}
+}
+
+func TestParseRangeSet(t *testing.T) {
+	rs, err := ParseRangeSet("^1.0.0 || >=3.0.0 <3.5.0 || 5.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "^1.0.0 || >=3.0.0 <3.5.0 || =5.0.0"
+	if actual := rs.String(); actual != expected {
+		t.Errorf("got %q; want %q", actual, expected)
+	}
+	if _, err := ParseRangeSet("^1.0.0 ||"); err == nil {
+		t.Errorf("expected error parsing range set with empty alternative")
+	}
+}
+
+var normalizedRangeSets = map[string]string{
+	"^1.0.0 || ~1.2.0":          "^1.0.0",
+	"~1.2.0 || ^1.0.0":          "^1.0.0",
+	"^1.0.0 || ^1.0.0":          "^1.0.0",
+	">2.0.0 <1.0.0 || ^3.0.0":   "^3.0.0",
+	">2.0.0 <1.0.0":             "<0.0.0",
+	"^1.0.0 || ^2.0.0 || 1.5.0": "^1.0.0 || ^2.0.0",
+}
+
+func TestRangeSetNormalize(t *testing.T) {
+	for input, expected := range normalizedRangeSets {
+		if actual := MustParseRangeSet(input).Normalize().String(); actual != expected {
+			t.Errorf("normalizing %q gave %q; want %q", input, actual, expected)
+		}
+	}
+}
+
+var simplifyVersions = MustParseList(
+	"1.0.0", "1.0.1", "1.1.0", "1.2.0", "1.2.1", "1.3.0", "2.0.0", "2.1.0", "3.0.0")
+
+var simplifiedRangeSets = map[string]string{
+	">=1.0.0 <2.1.0 || >=2.1.0 <4.0.0": "*",
+	"^1.0.0 || ^2.0.0 || ^3.0.0":       "*",
+	">=1.2.0 <1.3.0 || ^2.0.0":         "~1.2.0 || ^2.0.0",
+	"^1.2.0 || >=2.0.0 <2.1.0":         ">=1.2.0 <=2.0.0",
+	"^2.0.0 || ^3.0.0":                 ">=2.0.0",
+	">=0.0.0 <1.2.0":                   "<=1.1.0",
+	"=1.3.0":                           "=1.3.0",
+	"^1.3.0 || ~1.3.0":                 "=1.3.0",
+	"^4.0.0":                           "^4.0.0",
+}
+
+func TestRangeSetSimplify(t *testing.T) {
+	for input, expected := range simplifiedRangeSets {
+		rs := MustParseRangeSet(input)
+		actual := rs.Simplify(simplifyVersions)
+		if actual != expected {
+			t.Errorf("simplifying %q gave %q; want %q", input, actual, expected)
+		}
+		simplified := MustParseRangeSet(actual)
+		for _, v := range simplifyVersions {
+			if simplified.SatisfiedBy(v) != rs.SatisfiedBy(v) {
+				t.Errorf("simplified %q as %q, but they disagree on %s", input, actual, v)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package semv
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 )
 
@@ -79,9 +80,16 @@ func TestParseRange_Invalid(t *testing.T) {
 	if (r != Range{}) {
 		t.Errorf(`ParseRange("") did not return a zeroed range`)
 	}
+	for _, input := range []string{"1.0.0 foo", ">=1.0.0 <2.0.0 zz", "^1.0.0 ||", ">=1.0.0foo", "1.2.3.4", "=>1.0.0", ">", "^1.x"} {
+		if r, err := ParseRange(input); err == nil {
+			t.Errorf("ParseRange(%q): got %s; want an error for the invalid comparator", input, r)
+		}
+	}
 }
 
 var rangesToStrings = map[Range]string{
+	EqualTo(v1_0_0):                                                  "=1.0.0",
+	{}:                                                               "*",
 	LessThan(v1_0_0):                                                 "<1.0.0",
 	GreaterThan(v1_0_0):                                              ">1.0.0",
 	GreaterThanOrEqualTo(v1_0_0):                                     ">=1.0.0",
@@ -175,3 +183,182 @@ func (r Range) dump() string {
 	}
 	return string(b)
 }
+
+var intersections = []struct {
+	a, b     string
+	expected Range
+}{
+	{">=1.0.0", "<2.0.0", GreaterThanOrEqualToAndLessThan(v1_0_0, v2_0_0)},
+	{"^1.0.0", ">=1.0.0", GreaterThanOrEqualToAndLessThan(v1_0_0, v2_0_0)},
+	{">=1.0.0", ">1.0.0", GreaterThan(v1_0_0)},
+	{"<=2.0.0", "<2.0.0", LessThan(v2_0_0)},
+	{"<=2.0.0", "^1.0.0", GreaterThanOrEqualToAndLessThan(v1_0_0, v2_0_0)},
+	{"1.0.0", ">=1.0.0", EqualTo(v1_0_0)},
+}
+
+func TestIntersect(t *testing.T) {
+	for _, test := range intersections {
+		a, b := MustParseRange(test.a), MustParseRange(test.b)
+		for _, actual := range []Range{a.Intersect(b), b.Intersect(a)} {
+			if !actual.Equals(test.expected) {
+				t.Errorf("%q intersect %q gave %q; want %q", a, b, actual, test.expected)
+			}
+		}
+	}
+}
+
+func TestIntersect_Empty(t *testing.T) {
+	r := MustParseRange("<1.0.0").Intersect(MustParseRange(">2.0.0"))
+	for _, vs := range []string{"0.0.1", "1.0.0", "1.5.0", "2.0.0", "2.0.1"} {
+		if r.SatisfiedBy(MustParse(vs)) {
+			t.Errorf("expected empty range %q not to be satisfied by %q", r, vs)
+		}
+	}
+}
+
+var compoundRanges = map[string]string{
+	">=1.0.0 <2.0.0":    "^1.0.0",
+	">= 1.0.0 < 1.1.0":  "~1.0.0",
+	">=1.0.0 <=1.0.0":   "=1.0.0",
+	">1.0.0 >=1.0.0":    ">1.0.0",
+	">=1.2.0 <1.5.0":    ">=1.2.0 <1.5.0",
+	"1.2.0 - 1.5.0":     ">=1.2.0 <=1.5.0",
+	"^1.0.0 <1.5.0":     ">=1.0.0 <1.5.0",
+	">2.0.0 <1.0.0":     "<0.0.0",
+	"*":                 "*",
+	">=1.0.0 <=1.0.0-a": "<0.0.0",
+	"~1 >1.0.5":         ">1.0.5 <1.1.0",
+	">00 ~0":            ">0.0.0 <0.1.0",
+	">=v1.2.0 <V1.5.0":  ">=1.2.0 <1.5.0",
+}
+
+func TestParseRange_Compound(t *testing.T) {
+	for input, expected := range compoundRanges {
+		r, err := ParseRange(input)
+		if err != nil {
+			t.Errorf("parsing %q: unexpected error: %s", input, err)
+			continue
+		}
+		if actual := r.String(); actual != expected {
+			t.Errorf("parsing %q gave %q; want %q", input, actual, expected)
+		}
+		if !MustParseRange(r.String()).Equals(r) {
+			t.Errorf("%q did not reparse to an equal range", r)
+		}
+	}
+}
+
+var equalRanges = [][2]string{
+	{">=1.0.0 <=1.0.0", "=1.0.0"},
+	{"1.0.0", "==1.0.0"},
+	{">2.0.0 <1.0.0", ">5.0.0 <3.0.0"},
+	{">1.0.0 <1.0.0", "<0.0.0"},
+	{">=1.0.0 <2.0.0 <3.0.0", "^1.0.0"},
+}
+
+var unequalRanges = [][2]string{
+	{">0.9.9", ">=1.0.0"},
+	{">=1.0.0", ">1.0.0"},
+	{"^1.0.0", "~1.0.0"},
+}
+
+func TestEquals_SetBased(t *testing.T) {
+	for _, pair := range equalRanges {
+		a, b := MustParseRange(pair[0]), MustParseRange(pair[1])
+		if !a.Equals(b) || !b.Equals(a) {
+			t.Errorf("expected %q to equal %q", pair[0], pair[1])
+		}
+	}
+	for _, pair := range unequalRanges {
+		a, b := MustParseRange(pair[0]), MustParseRange(pair[1])
+		if a.Equals(b) || b.Equals(a) {
+			t.Errorf("expected %q not to equal %q", pair[0], pair[1])
+		}
+	}
+}
+
+func TestEquivalentIn(t *testing.T) {
+	a, b := MustParseRange(">0.9.9"), MustParseRange(">=1.0.0")
+	if !a.EquivalentIn(b, MustParseList("0.9.0", "0.9.9", "1.0.0", "1.1.0")) {
+		t.Errorf("expected %q to be equivalent to %q", a, b)
+	}
+	if a.EquivalentIn(b, MustParseList("0.9.9", "0.9.10", "1.0.0")) {
+		t.Errorf("expected %q not to be equivalent to %q when 0.9.10 is known", a, b)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	r := Range{Min: &v1_0_0, MinEqual: &v1_0_0, Max: &v2_0_0, MaxEqual: &v2_0_0}.Normalize()
+	if r.MinEqual != nil || r.MaxEqual != nil || r.Min == nil || r.Max == nil {
+		t.Errorf("expected exclusive bounds to be kept; got %s", r.dump())
+	}
+	if !MustParseRange(">3.0.0 <1.0.0").Normalize().IsEmpty() {
+		t.Errorf("expected normalized unsatisfiable range to be empty")
+	}
+	if MustParseRange("*").Normalize() != (Range{}) {
+		t.Errorf("expected unbounded range to normalize to the zero Range")
+	}
+}
+
+func TestRange_IsEmpty(t *testing.T) {
+	tests := map[string]bool{
+		">3.0.0 <1.0.0":             true,
+		"<0.0.0":                    true,
+		">1.0.0 <1.0.1":             true,
+		">1.0.0+build.1 <1.0.1":     true,
+		">1.2.9 <1.2.10":            true,
+		">1.9.9 <2.0.0":             false,
+		">1.0.0 <=1.0.0":            true,
+		">=1.0.0 <1.0.0":            true,
+		">=1.0.0 <1.0.1":            false,
+		">1.0.0 <=1.0.1":            false,
+		">1.0.0 <1.0.2":             false,
+		">1.0.0 <1.1.0":             false,
+		">=1.0.0 <=1.0.0":           false,
+		"<=0.0.0":                   false,
+		"<0.0.1":                    false,
+		"<0.0.0-rc.1":               false,
+		">1.0.0-rc.1 <1.0.0-rc.2":   false,
+		">=1.0.0-rc.1 <1.0.0-rc.1":  true,
+		">1.0.0 <1.0.1-rc.1":        false,
+		">=1.0.0-rc.1 <=1.0.0-rc.1": false,
+		"*":                         false,
+	}
+	for input, expected := range tests {
+		if actual := MustParseRange(input).IsEmpty(); actual != expected {
+			t.Errorf("%q.IsEmpty(): got %t; want %t", input, actual, expected)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	r := MustParseRange(">=1.0.0 <2.0.0")
+	for _, vs := range []string{"1.0.0", "1.5.0-beta", "1.9.9", "2.0.0-rc.1"} {
+		if !r.Contains(MustParse(vs)) {
+			t.Errorf("expected %q to contain %q", r, vs)
+		}
+	}
+	for _, vs := range []string{"0.9.9", "1.0.0-rc.1", "2.0.0"} {
+		if r.Contains(MustParse(vs)) {
+			t.Errorf("expected %q not to contain %q", r, vs)
+		}
+	}
+	if !MustParseRange("<=1.0.0").Contains(v1_0_0) || MustParseRange(">1.0.0").Contains(v1_0_0) {
+		t.Errorf("inclusive and exclusive bounds not respected")
+	}
+}
+
+func TestSplitComparators(t *testing.T) {
+	tests := map[string]string{
+		">=1.0.0 <2.0.0":    `[">=1.0.0" "<2.0.0"]`,
+		">= 1.0.0  < 2.0.0": `[">=1.0.0" "<2.0.0"]`,
+		"1.0.0 - 2.0.0":     `["1.0.0" "-" "2.0.0"]`,
+		"^1.2 >= ":          `["^1.2" ">="]`,
+		"  ":                `[]`,
+	}
+	for input, expected := range tests {
+		if actual := fmt.Sprintf("%q", SplitComparators(input)); actual != expected {
+			t.Errorf("SplitComparators(%q): got %s; want %s", input, actual, expected)
+		}
+	}
+}
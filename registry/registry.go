@@ -0,0 +1,165 @@
+/*
+Package registry provides an in-memory catalog of the versions available for
+each of a set of named components, which is safe for concurrent use.
+
+Reads never block: each call to Registry.Snapshot returns an immutable view of
+the registry, which is replaced wholesale (copy-on-write) by each Publish or
+Remove. Subscribers can Watch a component for new versions satisfying a range.
+*/
+package registry
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/samsalisbury/semv"
+)
+
+type (
+	// Registry holds the versions available for each named component. The
+	// zero Registry is not usable; create one using New.
+	Registry struct {
+		// mu serialises writers, and guards watches.
+		mu sync.Mutex
+		// notifyMu is held while delivering events, so that watchers see
+		// events in the order the changes were made.
+		notifyMu sync.Mutex
+		snapshot atomic.Pointer[Snapshot]
+		watches  map[*watch]struct{}
+	}
+	// Snapshot is an immutable view of a Registry at a point in time.
+	Snapshot struct {
+		components map[string]semv.VersionSet
+	}
+	// Event describes a newly published version of a component.
+	Event struct {
+		Name    string
+		Version semv.Version
+	}
+	watch struct {
+		name string
+		rng  semv.Range
+		f    func(Event)
+	}
+)
+
+// New returns a new, empty Registry.
+func New() *Registry {
+	r := &Registry{watches: map[*watch]struct{}{}}
+	r.snapshot.Store(&Snapshot{components: map[string]semv.VersionSet{}})
+	return r
+}
+
+// Snapshot returns the current state of the registry. It never blocks, and
+// the Snapshot returned is never modified, so it may be read freely whilst
+// other goroutines publish and remove versions.
+func (r *Registry) Snapshot() *Snapshot {
+	return r.snapshot.Load()
+}
+
+// Publish adds versions of the named component to the registry, and notifies
+// any watchers of those versions which are new and satisfy their range.
+func (r *Registry) Publish(name string, versions ...semv.Version) {
+	r.update(name, func(s semv.VersionSet) semv.VersionSet {
+		return s.Insert(versions...)
+	})
+}
+
+// Remove removes versions of the named component from the registry. Versions
+// which are not present are ignored. Unlike a yanked version, as recorded by
+// semv.StatusMap, a removed version can no longer be selected at all, even by
+// an exact pin.
+func (r *Registry) Remove(name string, versions ...semv.Version) {
+	r.update(name, func(s semv.VersionSet) semv.VersionSet {
+		return s.Remove(versions...)
+	})
+}
+
+// update replaces the versions of the named component with the result of f,
+// and delivers events for any versions that were added.
+func (r *Registry) update(name string, f func(semv.VersionSet) semv.VersionSet) {
+	r.mu.Lock()
+	old := r.snapshot.Load()
+	before := old.components[name]
+	after := f(before)
+	next := &Snapshot{components: make(map[string]semv.VersionSet, len(old.components)+1)}
+	for n, s := range old.components {
+		next.components[n] = s
+	}
+	if after.Len() == 0 {
+		delete(next.components, name)
+	} else {
+		next.components[name] = after
+	}
+	r.snapshot.Store(next)
+
+	var deliveries []func()
+	for w := range r.watches {
+		if w.name != name {
+			continue
+		}
+		for _, v := range after.AllSatisfying(w.rng) {
+			if !before.Contains(v) {
+				notify, e := w.f, Event{name, v}
+				deliveries = append(deliveries, func() { notify(e) })
+			}
+		}
+	}
+	r.notifyMu.Lock()
+	r.mu.Unlock()
+	defer r.notifyMu.Unlock()
+	for _, deliver := range deliveries {
+		deliver()
+	}
+}
+
+// Watch registers f to be called with an Event each time a version of the
+// named component satisfying rng is published, which was not already in the
+// registry. It returns a function which cancels the watch. Events already
+// being delivered when cancel is called may still be received.
+//
+// Events are delivered synchronously, in the order versions were published,
+// by the goroutine which published them, after the new versions are visible
+// in Snapshot. For this reason f must not itself call Publish or Remove, and
+// should return quickly. To process events asynchronously, f can send them
+// on a buffered channel.
+func (r *Registry) Watch(name string, rng semv.Range, f func(Event)) (cancel func()) {
+	w := &watch{name, rng, f}
+	r.mu.Lock()
+	r.watches[w] = struct{}{}
+	r.mu.Unlock()
+	return func() {
+		r.mu.Lock()
+		delete(r.watches, w)
+		r.mu.Unlock()
+	}
+}
+
+// Names returns the names of all components with at least one version, in
+// alphabetical order.
+func (s *Snapshot) Names() []string {
+	names := make([]string, 0, len(s.components))
+	for name := range s.components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Versions returns the versions of the named component, from lowest to
+// highest. If the component is unknown, the list is empty.
+func (s *Snapshot) Versions(name string) semv.VersionList {
+	return s.components[name].List()
+}
+
+// Set returns the versions of the named component as a VersionSet.
+func (s *Snapshot) Set(name string) semv.VersionSet {
+	return s.components[name]
+}
+
+// GreatestSatisfying returns the greatest version of the named component
+// which satisfies rng. If there is none, the second return value is false.
+func (s *Snapshot) GreatestSatisfying(name string, rng semv.Range) (semv.Version, bool) {
+	return s.components[name].GreatestSatisfying(rng)
+}
@@ -0,0 +1,113 @@
+package registry
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/samsalisbury/semv"
+)
+
+func TestPublishAndRemove(t *testing.T) {
+	r := New()
+	r.Publish("api", semv.MustParseList("1.0.0", "1.1.0", "2.0.0")...)
+	r.Publish("web", semv.MustParse("0.1.0"))
+	before := r.Snapshot()
+	r.Remove("api", semv.MustParse("2.0.0"))
+	r.Remove("web", semv.MustParse("0.1.0"))
+	after := r.Snapshot()
+
+	if v, ok := before.GreatestSatisfying("api", semv.MustParseRange(">=1.0.0")); !ok || v.String() != "2.0.0" {
+		t.Errorf("got %q from snapshot before removal; want 2.0.0", v)
+	}
+	if v, ok := after.GreatestSatisfying("api", semv.MustParseRange(">=1.0.0")); !ok || v.String() != "1.1.0" {
+		t.Errorf("got %q from snapshot after removal; want 1.1.0", v)
+	}
+	if names := fmt.Sprint(before.Names()); names != "[api web]" {
+		t.Errorf("got names %s before removal; want [api web]", names)
+	}
+	if names := fmt.Sprint(after.Names()); names != "[api]" {
+		t.Errorf("got names %s after removal; want [api]", names)
+	}
+	if vl := after.Versions("nope"); len(vl) != 0 {
+		t.Errorf("got versions %v for unknown component", vl)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	r := New()
+	r.Publish("api", semv.MustParse("1.0.0"))
+	var events []string
+	cancel := r.Watch("api", semv.MustParseRange("^1.0.0"), func(e Event) {
+		events = append(events, e.Name+"@"+e.Version.String())
+		if _, ok := r.Snapshot().Set(e.Name).GreatestSatisfying(semv.EqualTo(e.Version)); !ok {
+			t.Errorf("%s was not visible in the snapshot when notified", e.Version)
+		}
+	})
+	r.Publish("api", semv.MustParseList("1.0.0", "1.1.0", "2.0.0")...)
+	r.Publish("web", semv.MustParse("1.2.0"))
+	r.Remove("api", semv.MustParse("1.1.0"))
+	r.Publish("api", semv.MustParse("1.1.0"))
+	cancel()
+	r.Publish("api", semv.MustParse("1.2.0"))
+
+	expected := "[api@1.1.0 api@1.1.0]"
+	if actual := fmt.Sprint(events); actual != expected {
+		t.Errorf("got events %s; want %s", actual, expected)
+	}
+}
+
+// TestConcurrentAccess is intended to be run with -race.
+func TestConcurrentAccess(t *testing.T) {
+	r := New()
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	cancel := r.Watch("api", semv.MustParseRange(">=0.0.0"), func(e Event) {
+		mu.Lock()
+		seen[e.Version.String()] = true
+		mu.Unlock()
+	})
+	defer cancel()
+
+	const writers, versionsPerWriter = 4, 50
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < versionsPerWriter; i++ {
+				v := semv.NewMajorMinorPatch(w, i, 0)
+				r.Publish("api", v)
+				if i%10 == 0 {
+					r.Remove("api", v)
+				}
+			}
+		}(w)
+	}
+	for reader := 0; reader < 4; reader++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				s := r.Snapshot()
+				vl := s.Versions("api")
+				for j := 1; j < len(vl); j++ {
+					if !vl[j-1].Less(vl[j]) {
+						t.Errorf("snapshot versions out of order: %v", vl)
+						return
+					}
+				}
+				s.GreatestSatisfying("api", semv.MustParseRange("^1.0.0"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	expected := writers * (versionsPerWriter - versionsPerWriter/10)
+	if n := len(r.Snapshot().Versions("api")); n != expected {
+		t.Errorf("got %d versions; want %d", n, expected)
+	}
+	if len(seen) != writers*versionsPerWriter {
+		t.Errorf("got %d events; want %d", len(seen), writers*versionsPerWriter)
+	}
+}
@@ -0,0 +1,85 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/samsalisbury/semv"
+)
+
+type (
+	// Catalog provides the resolver with the versions available for each
+	// package, and the dependencies of each of those versions.
+	Catalog interface {
+		// Versions returns all known versions of the named package.
+		Versions(name string) (semv.VersionList, error)
+		// Dependencies returns the dependencies of a specific version of the
+		// named package, keyed by dependency package name.
+		Dependencies(name string, v semv.Version) (map[string]semv.Range, error)
+	}
+	// MemoryCatalog is a Catalog backed by an in-memory map of package names
+	// to releases. It is mainly useful for tests and fixtures.
+	MemoryCatalog map[string][]Release
+	// Release is a single version of a package, along with the dependencies of
+	// that version.
+	Release struct {
+		Version semv.Version
+		Deps    map[string]semv.Range
+	}
+	// UnknownPackage is an error returned by catalogs when asked about a
+	// package they know nothing about.
+	UnknownPackage struct {
+		Name string
+	}
+	// UnknownVersion is an error returned by catalogs when asked about a
+	// version of a package that does not exist.
+	UnknownVersion struct {
+		Name    string
+		Version semv.Version
+	}
+)
+
+func (err UnknownPackage) Error() string {
+	return fmt.Sprintf("unknown package %q", err.Name)
+}
+
+func (err UnknownVersion) Error() string {
+	return fmt.Sprintf("unknown version %s of package %q", err.Version, err.Name)
+}
+
+// Add appends a release of the named package to the catalog, parsing the
+// version and dependency ranges using semv.MustParse and semv.MustParseRange.
+// It is intended for building fixtures, and so panics on malformed input.
+func (c MemoryCatalog) Add(name, version string, deps map[string]string) {
+	r := Release{Version: semv.MustParse(version), Deps: map[string]semv.Range{}}
+	for dep, rng := range deps {
+		r.Deps[dep] = semv.MustParseRange(rng)
+	}
+	c[name] = append(c[name], r)
+}
+
+// Versions returns all versions of the named package.
+func (c MemoryCatalog) Versions(name string) (semv.VersionList, error) {
+	releases, ok := c[name]
+	if !ok {
+		return nil, UnknownPackage{name}
+	}
+	vl := make(semv.VersionList, len(releases))
+	for i, r := range releases {
+		vl[i] = r.Version
+	}
+	return vl, nil
+}
+
+// Dependencies returns the dependencies of version v of the named package.
+func (c MemoryCatalog) Dependencies(name string, v semv.Version) (map[string]semv.Range, error) {
+	releases, ok := c[name]
+	if !ok {
+		return nil, UnknownPackage{name}
+	}
+	for _, r := range releases {
+		if r.Version.Equals(v) {
+			return r.Deps, nil
+		}
+	}
+	return nil, UnknownVersion{name, v}
+}
@@ -0,0 +1,313 @@
+/*
+Package resolver finds a consistent set of package versions satisfying a set
+of inter-package version constraints.
+
+Resolution prefers the highest available version of each package, in the same
+way that semv.VersionList.GreatestSatisfying does for a single package, and
+backtracks to lower versions when a choice leads to a conflict. Each conflict
+is traced back to the choices which caused it, so that the resolver backjumps
+straight to the most recent of them, rather than retrying every version of
+the packages chosen since, and never again explores an assignment containing
+the same choices. When no consistent assignment exists, Resolve returns a
+NoSolution error carrying a Derivation tree which explains why each attempted
+choice failed.
+*/
+package resolver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samsalisbury/semv"
+)
+
+type (
+	// Solution maps package names to their resolved versions.
+	Solution map[string]semv.Version
+	// Requirement is a single constraint on a package, along with the package
+	// that imposed it.
+	Requirement struct {
+		Package string
+		Range   semv.Range
+		// RequiredBy is the name@version of the package which declared this
+		// requirement, or the empty string for root requirements.
+		RequiredBy string
+	}
+	// Derivation is a node in the tree explaining why resolution failed.
+	// Each node describes either a package for which no version could be
+	// selected, or a specific version which was rejected. Causes holds the
+	// nodes explaining the failure in more detail.
+	Derivation struct {
+		Package string
+		// Version is nil for package nodes, and set for nodes describing why
+		// a particular version of Package was rejected.
+		Version *semv.Version
+		// Requirements are the constraints that were in force on Package.
+		Requirements []Requirement
+		Reason       string
+		Causes       []*Derivation
+	}
+	// NoSolution is the error returned by Resolve when the requirements
+	// cannot be satisfied.
+	NoSolution struct {
+		Derivation *Derivation
+	}
+	// state is a partial solution explored by the resolver.
+	state struct {
+		assigned Solution
+		reqs     map[string][]Requirement
+		queue    []string
+	}
+	// nogood is a partial assignment which no solution contains. Only
+	// packages selected by the resolver are included, since the root
+	// requirements are common to every assignment.
+	nogood map[string]semv.Version
+	// failure explains why no solution extends a partial assignment.
+	failure struct {
+		d *Derivation
+		// cause is the part of the assignment responsible for the failure.
+		cause nogood
+	}
+	resolver struct {
+		catalog Catalog
+		// nogoods are the causes of the failures found so far.
+		nogoods []nogood
+	}
+)
+
+func (err NoSolution) Error() string {
+	return "no solution found:\n" + err.Derivation.String()
+}
+
+func (r Requirement) String() string {
+	by := r.RequiredBy
+	if by == "" {
+		by = "root"
+	}
+	return fmt.Sprintf("%s %s (required by %s)", r.Package, r.Range, by)
+}
+
+// String renders the derivation as an indented tree.
+func (d *Derivation) String() string {
+	buf := &bytes.Buffer{}
+	d.write(buf, 0)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func (d *Derivation) write(buf *bytes.Buffer, depth int) {
+	indent := strings.Repeat("  ", depth)
+	name := d.Package
+	if d.Version != nil {
+		name += "@" + d.Version.String()
+	}
+	fmt.Fprintf(buf, "%s%s: %s\n", indent, name, d.Reason)
+	for _, r := range d.Requirements {
+		fmt.Fprintf(buf, "%s  - %s\n", indent, r)
+	}
+	for _, c := range d.Causes {
+		c.write(buf, depth+1)
+	}
+}
+
+// Resolve finds a version for every package reachable from the root
+// requirements, such that every requirement is satisfied. Higher versions are
+// always tried first, so the solution is the one a user would expect from
+// repeatedly applying GreatestSatisfying, wherever that is consistent.
+//
+// If the requirements cannot be satisfied, the error returned is a NoSolution.
+// Errors returned by the catalog abort resolution and are returned as-is.
+func Resolve(c Catalog, root map[string]semv.Range) (Solution, error) {
+	s := state{assigned: Solution{}, reqs: map[string][]Requirement{}}
+	for _, name := range sortedKeys(root) {
+		s.reqs[name] = append(s.reqs[name], Requirement{name, root[name], ""})
+		s.queue = append(s.queue, name)
+	}
+	solution, f, err := (&resolver{catalog: c}).solve(s)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil {
+		return nil, NoSolution{f.d}
+	}
+	return solution, nil
+}
+
+// solve returns either a complete solution, or a failure explaining why none
+// exists from the given state, or an error from the catalog.
+func (r *resolver) solve(s state) (Solution, *failure, error) {
+	for len(s.queue) != 0 {
+		if _, ok := s.assigned[s.queue[0]]; !ok {
+			break
+		}
+		s.queue = s.queue[1:]
+	}
+	if len(s.queue) == 0 {
+		return s.assigned, nil, nil
+	}
+	name := s.queue[0]
+	reqs := s.reqs[name]
+	for _, ng := range r.nogoods {
+		if ng.in(s.assigned) {
+			return nil, &failure{&Derivation{Package: name, Requirements: reqs,
+				Reason: "ruled out by an earlier conflict between " + ng.String()}, ng}, nil
+		}
+	}
+	available, err := r.catalog.Versions(name)
+	var unknown UnknownPackage
+	if errors.As(err, &unknown) {
+		return nil, r.fail(&Derivation{Package: name, Requirements: reqs, Reason: "unknown package"},
+			causeOf(s, reqs)), nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	candidates := satisfyingAll(available.SortedDesc(), reqs)
+	if len(candidates) == 0 {
+		return nil, r.fail(&Derivation{Package: name, Requirements: reqs,
+			Reason: "no version satisfies all requirements"}, causeOf(s, reqs)), nil
+	}
+	failed := &Derivation{Package: name, Requirements: reqs,
+		Reason: "no candidate version could be selected"}
+	cause := causeOf(s, reqs)
+	for _, candidate := range candidates {
+		next, conflict, err := r.choose(s, name, candidate)
+		if err != nil {
+			return nil, nil, err
+		}
+		if conflict == nil {
+			var solution Solution
+			if solution, conflict, err = r.solve(next); err != nil {
+				return nil, nil, err
+			}
+			if conflict == nil {
+				return solution, nil, nil
+			}
+			if _, ok := conflict.cause[name]; !ok {
+				// The failure does not depend on this choice, so no other
+				// version of name can avoid it.
+				return nil, conflict, nil
+			}
+			v := candidate
+			conflict = &failure{&Derivation{Package: name, Version: &v,
+				Reason: "rejected", Causes: []*Derivation{conflict.d}}, conflict.cause}
+		}
+		failed.Causes = append(failed.Causes, conflict.d)
+		for n, v := range conflict.cause {
+			if n != name {
+				cause[n] = v
+			}
+		}
+	}
+	return nil, r.fail(failed, cause), nil
+}
+
+// fail records cause as a nogood, and returns the failure.
+func (r *resolver) fail(d *Derivation, cause nogood) *failure {
+	r.nogoods = append(r.nogoods, cause)
+	return &failure{d, cause}
+}
+
+// choose returns a new state with name assigned to version v, and the
+// dependencies of v added as requirements. If any of those dependencies
+// conflict with an already selected version, a failure explaining the
+// conflict is returned instead.
+func (r *resolver) choose(s state, name string, v semv.Version) (state, *failure, error) {
+	deps, err := r.catalog.Dependencies(name, v)
+	if err != nil {
+		return state{}, nil, err
+	}
+	next := state{
+		assigned: make(Solution, len(s.assigned)+1),
+		reqs:     make(map[string][]Requirement, len(s.reqs)+len(deps)),
+		queue:    s.queue[1:len(s.queue):len(s.queue)],
+	}
+	for n, av := range s.assigned {
+		next.assigned[n] = av
+	}
+	next.assigned[name] = v
+	for n, rs := range s.reqs {
+		next.reqs[n] = rs[:len(rs):len(rs)]
+	}
+	by := name + "@" + v.String()
+	for _, dep := range sortedKeys(deps) {
+		req := Requirement{dep, deps[dep], by}
+		if selected, ok := next.assigned[dep]; ok && !req.Range.SatisfiedBy(selected) {
+			return state{}, &failure{&Derivation{Package: name, Version: &v,
+				Requirements: append(next.reqs[dep], req),
+				Reason:       fmt.Sprintf("conflicts with selected version %s of %s", selected, dep)},
+				nogood{name: v, dep: selected}}, nil
+		}
+		next.reqs[dep] = append(next.reqs[dep], req)
+		next.queue = append(next.queue, dep)
+	}
+	return next, nil, nil
+}
+
+// causeOf returns the selected versions which imposed reqs.
+func causeOf(s state, reqs []Requirement) nogood {
+	cause := nogood{}
+	for _, req := range reqs {
+		if req.RequiredBy == "" {
+			continue
+		}
+		// RequiredBy is name@version, and versions never contain "@".
+		name := req.RequiredBy[:strings.LastIndex(req.RequiredBy, "@")]
+		cause[name] = s.assigned[name]
+	}
+	return cause
+}
+
+// in returns true if every selection in ng is also in assigned.
+func (ng nogood) in(assigned Solution) bool {
+	for name, v := range ng {
+		if selected, ok := assigned[name]; !ok || !selected.Equals(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the selections in ng, e.g. "a@1.0.0 and b@2.0.0", or "the
+// root requirements" if it is empty.
+func (ng nogood) String() string {
+	if len(ng) == 0 {
+		return "the root requirements"
+	}
+	names := make([]string, 0, len(ng))
+	for name := range ng {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		names[i] = name + "@" + ng[name].String()
+	}
+	return strings.Join(names, " and ")
+}
+
+// satisfyingAll returns the versions in vl which satisfy every requirement,
+// preserving their order.
+func satisfyingAll(vl semv.VersionList, reqs []Requirement) semv.VersionList {
+	var out semv.VersionList
+outer:
+	for _, v := range vl {
+		for _, req := range reqs {
+			if !req.Range.SatisfiedBy(v) {
+				continue outer
+			}
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+func sortedKeys(m map[string]semv.Range) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
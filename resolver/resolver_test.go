@@ -0,0 +1,184 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/samsalisbury/semv"
+)
+
+func newFixtureCatalog() MemoryCatalog {
+	c := MemoryCatalog{}
+	c.Add("app", "1.0.0", map[string]string{"log": "^1.0.0", "http": "^2.0.0"})
+	c.Add("log", "1.0.0", nil)
+	c.Add("log", "1.1.0", nil)
+	c.Add("log", "1.2.0", nil)
+	c.Add("log", "2.0.0", nil)
+	c.Add("http", "2.0.0", map[string]string{"log": "^1.0.0"})
+	c.Add("http", "2.1.0", map[string]string{"log": "~1.1.0"})
+	c.Add("http", "2.2.0", map[string]string{"log": "^2.0.0"})
+	c.Add("http", "3.0.0", nil)
+	c.Add("web", "1.0.0", map[string]string{"http": "^2.0.0"})
+	c.Add("web", "2.0.0", map[string]string{"http": "~2.2.0"})
+	return c
+}
+
+func root(reqs map[string]string) map[string]semv.Range {
+	out := map[string]semv.Range{}
+	for name, r := range reqs {
+		out[name] = semv.MustParseRange(r)
+	}
+	return out
+}
+
+var resolveTests = []struct {
+	root     map[string]string
+	expected map[string]string
+}{
+	{
+		root:     map[string]string{"log": ">=1.0.0"},
+		expected: map[string]string{"log": "2.0.0"},
+	},
+	{
+		root:     map[string]string{"log": "^1.0.0"},
+		expected: map[string]string{"log": "1.2.0"},
+	},
+	{
+		// http 2.2.0 needs log 2, which conflicts with app's ^1 requirement,
+		// so the resolver must backtrack to http 2.1.0, then pick log 1.1.x.
+		root:     map[string]string{"app": "1.0.0"},
+		expected: map[string]string{"app": "1.0.0", "http": "2.1.0", "log": "1.1.0"},
+	},
+	{
+		// Here http is selected before log, so http 2.2.0 is discarded when
+		// no version of log satisfies both ^1.0.0 and ^2.0.0.
+		root:     map[string]string{"http": "^2.0.0", "log": "^1.0.0"},
+		expected: map[string]string{"http": "2.1.0", "log": "1.1.0"},
+	},
+	{
+		// Here log is selected before http, so http 2.2.0 and 2.1.0 must
+		// be rejected because log 1.2.0 is already selected.
+		root:     map[string]string{"log": "^1.0.0", "web": "1.0.0"},
+		expected: map[string]string{"http": "2.0.0", "log": "1.2.0", "web": "1.0.0"},
+	},
+}
+
+func TestResolve(t *testing.T) {
+	c := newFixtureCatalog()
+	for _, test := range resolveTests {
+		actual, err := Resolve(c, root(test.root))
+		if err != nil {
+			t.Errorf("resolving %v: unexpected error: %s", test.root, err)
+			continue
+		}
+		if len(actual) != len(test.expected) {
+			t.Errorf("resolving %v: got %v; want %v", test.root, actual, test.expected)
+			continue
+		}
+		for name, vs := range test.expected {
+			if v, ok := actual[name]; !ok || !v.Equals(semv.MustParse(vs)) {
+				t.Errorf("resolving %v: got %s@%s; want %s", test.root, name, v, vs)
+			}
+		}
+	}
+}
+
+func TestResolve_NoSolution(t *testing.T) {
+	c := newFixtureCatalog()
+	_, err := Resolve(c, root(map[string]string{"log": "^1.0.0", "web": "2.0.0"}))
+	ns, ok := err.(NoSolution)
+	if !ok {
+		t.Fatalf("got error %v; want a NoSolution", err)
+	}
+	d := ns.Derivation
+	if d.Package != "log" || len(d.Causes) != 3 {
+		t.Fatalf("expected every version of log to be tried; got:\n%s", d)
+	}
+	if !strings.Contains(err.Error(), "conflicts with selected version 1.2.0 of log") {
+		t.Errorf("error does not explain the conflict:\n%s", err)
+	}
+}
+
+func TestResolve_UnknownPackage(t *testing.T) {
+	c := newFixtureCatalog()
+	_, err := Resolve(c, root(map[string]string{"nope": "^1.0.0"}))
+	ns, ok := err.(NoSolution)
+	if !ok {
+		t.Fatalf("got error %v; want a NoSolution", err)
+	}
+	if ns.Derivation.Reason != "unknown package" {
+		t.Errorf("got reason %q; want %q", ns.Derivation.Reason, "unknown package")
+	}
+}
+
+func TestResolve_Unsatisfiable(t *testing.T) {
+	c := newFixtureCatalog()
+	_, err := Resolve(c, root(map[string]string{"log": "^3.0.0"}))
+	ns, ok := err.(NoSolution)
+	if !ok {
+		t.Fatalf("got error %v; want a NoSolution", err)
+	}
+	if len(ns.Derivation.Requirements) != 1 || len(ns.Derivation.Causes) != 0 {
+		t.Errorf("got derivation:\n%s", ns.Derivation)
+	}
+}
+
+// countingCatalog counts the calls to Dependencies, which the resolver makes
+// once for each version it tries.
+type countingCatalog struct {
+	MemoryCatalog
+	calls int
+}
+
+func (c *countingCatalog) Dependencies(name string, v semv.Version) (map[string]semv.Range, error) {
+	c.calls++
+	return c.MemoryCatalog.Dependencies(name, v)
+}
+
+func TestResolve_Backjumping(t *testing.T) {
+	// Each of the 20 packages a01 to a20 has two versions, so naive
+	// backtracking would try over a million assignments before finding
+	// that only a01 is responsible for the conflict over b.
+	c := &countingCatalog{MemoryCatalog: MemoryCatalog{}}
+	reqs := map[string]string{}
+	for i := 1; i <= 20; i++ {
+		name := fmt.Sprintf("a%02d", i)
+		reqs[name] = "*"
+		c.Add(name, "1.0.0", nil)
+		if i == 1 {
+			c.Add(name, "2.0.0", map[string]string{"b": "^2.0.0"})
+		} else {
+			c.Add(name, "2.0.0", nil)
+		}
+	}
+	c.Add("b", "1.0.0", nil)
+	solution, err := Resolve(c, root(reqs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := solution["a01"]; !v.Equals(semv.MustParse("1.0.0")) {
+		t.Errorf("got a01@%s; want a01@1.0.0", v)
+	}
+	if v := solution["a20"]; !v.Equals(semv.MustParse("2.0.0")) {
+		t.Errorf("got a20@%s; want a20@2.0.0", v)
+	}
+	if c.calls > 40 {
+		t.Errorf("tried %d versions; want at most 40", c.calls)
+	}
+
+	// When the root requirements themselves conflict, the failure is found
+	// once, without retrying any of the choices made before it.
+	c.calls = 0
+	reqs["zz"] = "^1.0.0"
+	c.Add("zz", "2.0.0", nil)
+	_, err = Resolve(c, root(reqs))
+	var ns NoSolution
+	if !errors.As(err, &ns) || ns.Derivation.Package != "zz" {
+		t.Fatalf("got error %v; want a NoSolution for zz", err)
+	}
+	if c.calls > 21 {
+		t.Errorf("tried %d versions; want at most 21", c.calls)
+	}
+}
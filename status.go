@@ -0,0 +1,184 @@
+package semv
+
+import "fmt"
+
+type (
+	// Status is metadata describing the availability of a released version,
+	// as recorded by package registries.
+	Status struct {
+		// Yanked versions have been withdrawn, and are not selected unless
+		// they are exactly pinned.
+		Yanked bool
+		// Deprecated is a message explaining why a version is deprecated. If
+		// it is empty, the version is not deprecated.
+		Deprecated string
+		// Advisories are the IDs of security advisories affecting this
+		// version.
+		Advisories []string
+	}
+	// StatusMap records the Status of versions. Versions are matched exactly,
+	// including their prerelease and metadata, but not the format they were
+	// parsed in, so "1.0" and "1.0.0" share a Status, whilst "1.0.0+a" and
+	// "1.0.0+b" do not. The zero StatusMap is empty and ready to use.
+	StatusMap struct {
+		statuses map[versionKey]Status
+	}
+	// Release is a version along with its Status.
+	Release struct {
+		Version Version
+		Status  Status
+	}
+	// ReleaseList is a list of Releases. Its selection methods behave like
+	// those of VersionList, except that yanked versions are skipped.
+	ReleaseList []Release
+	// versionKey identifies a version exactly, ignoring its DefaultFormat.
+	versionKey struct {
+		major, minor, patch          int
+		pre, meta                    string
+		bigMajor, bigMinor, bigPatch string
+	}
+)
+
+// keyOf returns the key of v. The big fields are only included when they are
+// in use, since a stale one left behind by setting the int field directly
+// does not change the version.
+func keyOf(v Version) versionKey {
+	return versionKey{v.Major, v.Minor, v.Patch, v.Pre, v.Meta,
+		bigKey(v.Major, v.bigMajor), bigKey(v.Minor, v.bigMinor), bigKey(v.Patch, v.bigPatch)}
+}
+
+func bigKey(n int, b string) string {
+	if isBig(n, b) {
+		return b
+	}
+	return ""
+}
+
+// IsDeprecated returns true if the Deprecated message is not empty.
+func (s Status) IsDeprecated() bool {
+	return s.Deprecated != ""
+}
+
+// Warnings returns a human-readable warning for each problem recorded in this
+// status, suitable for showing to users who select the version.
+func (s Status) Warnings() []string {
+	var warnings []string
+	if s.Yanked {
+		warnings = append(warnings, "yanked")
+	}
+	if s.IsDeprecated() {
+		warnings = append(warnings, "deprecated: "+s.Deprecated)
+	}
+	for _, a := range s.Advisories {
+		warnings = append(warnings, fmt.Sprintf("affected by security advisory %s", a))
+	}
+	return warnings
+}
+
+// Set records the status of version v, replacing any existing status.
+func (sm *StatusMap) Set(v Version, s Status) {
+	if sm.statuses == nil {
+		sm.statuses = map[versionKey]Status{}
+	}
+	sm.statuses[keyOf(v)] = s
+}
+
+// Get returns the status of version v. Versions with no recorded status
+// have the zero Status.
+func (sm *StatusMap) Get(v Version) Status {
+	return sm.statuses[keyOf(v)]
+}
+
+// Yank marks version v as yanked, preserving the rest of its status.
+func (sm *StatusMap) Yank(v Version) {
+	s := sm.Get(v)
+	s.Yanked = true
+	sm.Set(v, s)
+}
+
+// Deprecate marks version v as deprecated with the given message, preserving
+// the rest of its status.
+func (sm *StatusMap) Deprecate(v Version, message string) {
+	s := sm.Get(v)
+	s.Deprecated = message
+	sm.Set(v, s)
+}
+
+// AddAdvisory records that version v is affected by the security advisory
+// with the given ID, preserving the rest of its status.
+func (sm *StatusMap) AddAdvisory(v Version, id string) {
+	s := sm.Get(v)
+	s.Advisories = append(s.Advisories[:len(s.Advisories):len(s.Advisories)], id)
+	sm.Set(v, s)
+}
+
+// Releases returns a ReleaseList pairing each version in vl with its status.
+func (sm *StatusMap) Releases(vl VersionList) ReleaseList {
+	rl := make(ReleaseList, len(vl))
+	for i, v := range vl {
+		rl[i] = Release{v, sm.Get(v)}
+	}
+	return rl
+}
+
+// Versions returns the versions in this list, without their statuses.
+func (rl ReleaseList) Versions() VersionList {
+	vl := make(VersionList, len(rl))
+	for i, r := range rl {
+		vl[i] = r.Version
+	}
+	return vl
+}
+
+// selectable returns true if release rel may be selected by range r. Yanked
+// releases are only selectable if r is an exact pin.
+func selectable(rel Release, r Range) bool {
+	if !r.SatisfiedBy(rel.Version) {
+		return false
+	}
+	if !rel.Status.Yanked {
+		return true
+	}
+	_, pinned := r.Exact()
+	return pinned
+}
+
+// GreatestSatisfying returns the greatest release in the list satisfying r,
+// skipping yanked releases unless r exactly pins them. Callers should show
+// the selected release's Status.Warnings to users. If no release is found,
+// the second return value is false.
+func (rl ReleaseList) GreatestSatisfying(r Range) (Release, bool) {
+	var greatest Release
+	found := false
+	for _, rel := range rl {
+		if selectable(rel, r) && (!found || greatest.Version.Less(rel.Version)) {
+			greatest, found = rel, true
+		}
+	}
+	return greatest, found
+}
+
+// LeastSatisfying is similar to GreatestSatisfying, except that it returns
+// the least release satisfying r.
+func (rl ReleaseList) LeastSatisfying(r Range) (Release, bool) {
+	var least Release
+	found := false
+	for _, rel := range rl {
+		if selectable(rel, r) && (!found || rel.Version.Less(least.Version)) {
+			least, found = rel, true
+		}
+	}
+	return least, found
+}
+
+// Filter returns the releases in this list which satisfy r, in their original
+// order, skipping yanked releases unless r exactly pins them.
+func (rl ReleaseList) Filter(r Range) ReleaseList {
+	out := ReleaseList{}
+	for _, rel := range rl {
+		if selectable(rel, r) {
+			out = append(out, rel)
+		}
+	}
+	return out
+}
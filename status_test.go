@@ -0,0 +1,103 @@
+package semv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newStatusMap() *StatusMap {
+	sm := &StatusMap{}
+	sm.Yank(MustParse("1.2.0"))
+	sm.Deprecate(MustParse("1.1.0"), "use 1.2.x")
+	sm.AddAdvisory(MustParse("1.1.0"), "GHSA-1234")
+	sm.Yank(MustParse("2.0.0+build.1"))
+	return sm
+}
+
+func TestStatusMap_MatchesExactly(t *testing.T) {
+	sm := newStatusMap()
+	if !sm.Get(MustParse("1.2")).Yanked {
+		t.Errorf("expected 1.2 to share the status of 1.2.0")
+	}
+	if sm.Get(MustParse("2.0.0")).Yanked || sm.Get(MustParse("2.0.0+build.2")).Yanked {
+		t.Errorf("expected status of 2.0.0+build.1 not to apply to other metadata")
+	}
+	v := MustParse("1.2.0")
+	v.SetFormat(MajorMinor)
+	if !sm.Get(v).Yanked {
+		t.Errorf("expected status lookup to ignore DefaultFormat")
+	}
+	if (&StatusMap{}).Get(v).Yanked {
+		t.Errorf("expected zero StatusMap to be empty")
+	}
+}
+
+func TestStatusMap_BigComponents(t *testing.T) {
+	sm := &StatusMap{}
+	sm.Yank(MustParse("1.0.20261016123045999000"))
+	if !sm.Get(MustParse("1.0.20261016123045999000")).Yanked {
+		t.Errorf("expected 1.0.20261016123045999000 to be yanked")
+	}
+	if sm.Get(MustParse("1.0.20261016123045999001")).Yanked {
+		t.Errorf("expected status not to apply to a different big patch")
+	}
+	// Setting the int field replaces the big component, so the stale big
+	// field must not affect the lookup.
+	v := MustParse("1.0.20261016123045999000")
+	v.Patch = 3
+	sm.Yank(MustParse("1.0.3"))
+	if !sm.Get(v).Yanked {
+		t.Errorf("expected %s to share the status of 1.0.3", v)
+	}
+}
+
+func TestStatusWarnings(t *testing.T) {
+	expected := []string{"deprecated: use 1.2.x", "affected by security advisory GHSA-1234"}
+	actual := newStatusMap().Get(MustParse("1.1.0")).Warnings()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got warnings %q; want %q", actual, expected)
+	}
+}
+
+func TestReleaseListSelection(t *testing.T) {
+	rl := newStatusMap().Releases(MustParseList("1.0.0", "1.1.0", "1.2.0", "2.0.0+build.1"))
+	tests := map[string]string{
+		"^1.0.0":  "1.1.0",
+		">=1.2.0": "",
+		"=1.2.0":  "1.2.0",
+		"2.0.0":   "2.0.0+build.1",
+		"<1.1.0":  "1.0.0",
+	}
+	for rs, expected := range tests {
+		rel, ok := rl.GreatestSatisfying(MustParseRange(rs))
+		if expected == "" {
+			if ok {
+				t.Errorf("expected nothing selectable for %q; got %q", rs, rel.Version)
+			}
+			continue
+		}
+		if !ok || rel.Version.String() != expected {
+			t.Errorf("GreatestSatisfying(%q) gave %q; want %q", rs, rel.Version, expected)
+		}
+	}
+	if rel, _ := rl.GreatestSatisfying(MustParseRange("^1.0.0")); !rel.Status.IsDeprecated() {
+		t.Errorf("expected selected release to report deprecation")
+	}
+	if rel, ok := rl.LeastSatisfying(MustParseRange(">1.0.0")); !ok || rel.Version.String() != "1.1.0" {
+		t.Errorf("LeastSatisfying(>1.0.0) gave %q; want 1.1.0", rel.Version)
+	}
+	assertVersionList(t, "Filter", rl.Filter(MustParseRange(">=1.0.0")).Versions(), "1.0.0", "1.1.0")
+}
+
+func TestRangeExact(t *testing.T) {
+	for _, rs := range []string{"1.0.0", "=1.0.0", ">=1.0.0 <=1.0.0"} {
+		if v, ok := MustParseRange(rs).Exact(); !ok || !v.Equals(v1_0_0) {
+			t.Errorf("expected %q to be an exact pin of 1.0.0", rs)
+		}
+	}
+	for _, rs := range []string{"^1.0.0", ">=1.0.0", "*"} {
+		if _, ok := MustParseRange(rs).Exact(); ok {
+			t.Errorf("expected %q not to be an exact pin", rs)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Package upgrade plans upgrades of installed dependencies to the versions
+available for them, like "npm outdated" and "cargo outdated".
+
+For each dependency, a Plan shows the version wanted by its declared range,
+the latest stable version, the latest stable version with the same major
+version as the installed one, and optionally a stepping-stone path through
+the latest release of each intermediate major version, for migrating one
+major version at a time.
+*/
+package upgrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samsalisbury/semv"
+)
+
+type (
+	// Dependency is an installed package.
+	Dependency struct {
+		Name      string
+		Installed semv.Version
+		// Range is the declared range the installed version was chosen
+		// from, e.g. in a manifest.
+		Range semv.Range
+	}
+	// Options control what NewPlan computes.
+	Options struct {
+		// SteppingStones adds a Path to each plan whose latest version has a
+		// higher major version than the installed one.
+		SteppingStones bool
+	}
+	// Plan describes the versions a dependency could be upgraded to. Versions
+	// which do not exist are nil.
+	Plan struct {
+		Name      string       `json:"-"`
+		Installed semv.Version `json:"installed"`
+		Range     string       `json:"range"`
+		// Wanted is the greatest available version satisfying Range.
+		Wanted *semv.Version `json:"wanted,omitempty"`
+		// Latest is the greatest available stable version, which may be lower
+		// than Installed, e.g. if Installed was withdrawn.
+		Latest *semv.Version `json:"latest,omitempty"`
+		// LatestInMajor is the greatest available stable version with the
+		// same major version as Installed.
+		LatestInMajor *semv.Version `json:"latestInMajor,omitempty"`
+		// Path is the sequence of upgrades from Installed to Latest, one major
+		// version at a time, if Options.SteppingStones was set. It consists of
+		// LatestInMajor, if it is greater than Installed, followed by the
+		// greatest stable version of each higher major version which has
+		// any, ending with Latest.
+		Path []semv.Version `json:"path,omitempty"`
+	}
+	// Report is a list of plans, as shown by "npm outdated".
+	Report []Plan
+)
+
+// NewPlan plans the upgrade of dep to the versions available.
+func NewPlan(dep Dependency, available semv.VersionList, opts Options) Plan {
+	p := Plan{Name: dep.Name, Installed: dep.Installed, Range: dep.Range.String()}
+	if v, ok := available.GreatestSatisfying(dep.Range); ok {
+		p.Wanted = &v
+	}
+	if v, ok := available.Stable().Max(); ok {
+		p.Latest = &v
+	}
+	// latestPerMajor is the greatest stable version of each major version
+	// higher than or equal to that of the installed version, in descending
+	// order.
+	var latestPerMajor semv.VersionList
+	for _, v := range available.SortedDesc() {
+		if v.IsPrerelease() {
+			continue
+		}
+		if d := semv.Diff(dep.Installed, v); d.Change == semv.MajorChange && d.Downgrade {
+			break
+		}
+		if n := len(latestPerMajor); n == 0 || !sameMajor(v, latestPerMajor[n-1]) {
+			latestPerMajor = append(latestPerMajor, v)
+		}
+	}
+	if len(latestPerMajor) == 0 {
+		return p
+	}
+	if last := latestPerMajor[len(latestPerMajor)-1]; sameMajor(last, dep.Installed) {
+		p.LatestInMajor = &last
+	}
+	if opts.SteppingStones && !sameMajor(*p.Latest, dep.Installed) {
+		for i := len(latestPerMajor) - 1; i >= 0; i-- {
+			if dep.Installed.Less(latestPerMajor[i]) {
+				p.Path = append(p.Path, latestPerMajor[i])
+			}
+		}
+	}
+	return p
+}
+
+func sameMajor(a, b semv.Version) bool {
+	return a.MajorString() == b.MajorString()
+}
+
+// Outdated returns true if Wanted or Latest is greater than Installed.
+func (p Plan) Outdated() bool {
+	return p.Wanted != nil && p.Installed.Less(*p.Wanted) || p.Latest != nil && p.Installed.Less(*p.Latest)
+}
+
+// Outdated returns the plans which are Outdated.
+func (r Report) Outdated() Report {
+	var out Report
+	for _, p := range r {
+		if p.Outdated() {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// WriteJSON writes the report to w as a JSON object with a member for each
+// plan, named after the dependency, like "npm outdated --json".
+func (r Report) WriteJSON(w io.Writer) error {
+	plans := make(map[string]Plan, len(r))
+	for _, p := range r {
+		plans[p.Name] = p
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(plans)
+}
+
+// WriteTable writes the report to w as a table with a row for each plan.
+// Versions which do not exist, and empty paths, are shown as "-". The PATH
+// column is only written if any of the plans has a Path.
+func (r Report) WriteTable(w io.Writer) error {
+	withPath := false
+	for _, p := range r {
+		withPath = withPath || len(p.Path) != 0
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprint(tw, "PACKAGE\tINSTALLED\tRANGE\tWANTED\tLATEST IN MAJOR\tLATEST")
+	if withPath {
+		fmt.Fprint(tw, "\tPATH")
+	}
+	fmt.Fprintln(tw)
+	for _, p := range r {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s", p.Name, p.Installed, p.Range,
+			orDash(p.Wanted), orDash(p.LatestInMajor), orDash(p.Latest))
+		if withPath {
+			path := make([]string, len(p.Path))
+			for i, v := range p.Path {
+				path[i] = v.String()
+			}
+			if len(path) == 0 {
+				path = []string{"-"}
+			}
+			fmt.Fprintf(tw, "\t%s", strings.Join(path, " -> "))
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+func orDash(v *semv.Version) string {
+	if v == nil {
+		return "-"
+	}
+	return v.String()
+}
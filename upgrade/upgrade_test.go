@@ -0,0 +1,113 @@
+package upgrade
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/samsalisbury/semv"
+)
+
+var available = semv.MustParseList(
+	"1.0.0", "1.2.0", "1.4.1", "1.5.0-beta.1",
+	"2.0.0", "2.3.0",
+	"4.0.0-rc.1", "4.1.0", "4.2.0",
+	"5.0.0-alpha",
+)
+
+func versionString(v *semv.Version) string {
+	if v == nil {
+		return "<nil>"
+	}
+	return v.String()
+}
+
+func TestNewPlan(t *testing.T) {
+	tests := []struct {
+		installed, rng                      string
+		wanted, latestInMajor, latest, path string
+	}{
+		{"1.2.0", "^1.2.0", "1.4.1", "1.4.1", "4.2.0", "1.4.1 2.3.0 4.2.0"},
+		{"1.4.1", "^1.4.1", "1.4.1", "1.4.1", "4.2.0", "2.3.0 4.2.0"},
+		{"2.0.0", "~2.0.0", "2.0.0", "2.3.0", "4.2.0", "2.3.0 4.2.0"},
+		{"4.2.0", "^4.0.0", "4.2.0", "4.2.0", "4.2.0", ""},
+		{"4.0.0-rc.1", "^4.0.0-rc.1", "4.2.0", "4.2.0", "4.2.0", ""},
+		{"3.0.0", "^3.0.0", "<nil>", "<nil>", "4.2.0", "4.2.0"},
+		{"6.0.0", "^6.0.0", "<nil>", "<nil>", "4.2.0", ""},
+		{"4.3.0", "^4.3.0", "<nil>", "4.2.0", "4.2.0", ""},
+	}
+	for _, test := range tests {
+		dep := Dependency{Name: "pkg", Installed: semv.MustParse(test.installed), Range: semv.MustParseRange(test.rng)}
+		p := NewPlan(dep, available, Options{SteppingStones: true})
+		path := ""
+		for i, v := range p.Path {
+			if i != 0 {
+				path += " "
+			}
+			path += v.String()
+		}
+		if actual := [...]string{versionString(p.Wanted), versionString(p.LatestInMajor), versionString(p.Latest), path}; actual != [...]string{test.wanted, test.latestInMajor, test.latest, test.path} {
+			t.Errorf("planning %s (%s): got wanted, latest in major, latest, path == %q; want %q", test.installed, test.rng,
+				actual, [...]string{test.wanted, test.latestInMajor, test.latest, test.path})
+		}
+	}
+}
+
+func TestNewPlan_NoSteppingStones(t *testing.T) {
+	dep := Dependency{Name: "pkg", Installed: semv.MustParse("1.2.0"), Range: semv.MustParseRange("^1.2.0")}
+	if p := NewPlan(dep, available, Options{}); p.Path != nil {
+		t.Errorf("got Path == %v; want nil", p.Path)
+	}
+}
+
+func newReport() Report {
+	return Report{
+		NewPlan(Dependency{"left-pad", semv.MustParse("1.2.0"), semv.MustParseRange("^1.2.0")}, available, Options{SteppingStones: true}),
+		NewPlan(Dependency{"right-pad", semv.MustParse("4.2.0"), semv.MustParseRange("^4.2.0")}, available, Options{SteppingStones: true}),
+	}
+}
+
+func TestReport_Outdated(t *testing.T) {
+	r := newReport().Outdated()
+	if len(r) != 1 || r[0].Name != "left-pad" {
+		t.Errorf("got %v; want only left-pad", r)
+	}
+}
+
+func TestReport_WriteTable(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newReport().WriteTable(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `PACKAGE    INSTALLED  RANGE   WANTED  LATEST IN MAJOR  LATEST  PATH
+left-pad   1.2.0      ^1.2.0  1.4.1   1.4.1            4.2.0   1.4.1 -> 2.3.0 -> 4.2.0
+right-pad  4.2.0      ^4.2.0  4.2.0   4.2.0            4.2.0   -
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", actual, expected)
+	}
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newReport()[:1].WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "left-pad": {
+    "installed": "1.2.0",
+    "range": "^1.2.0",
+    "wanted": "1.4.1",
+    "latest": "4.2.0",
+    "latestInMajor": "1.4.1",
+    "path": [
+      "1.4.1",
+      "2.3.0",
+      "4.2.0"
+    ]
+  }
+}
+`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", actual, expected)
+	}
+}
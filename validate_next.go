@@ -0,0 +1,141 @@
+package semv
+
+import "fmt"
+
+type (
+	// NextCheck identifies a rule enforced by ValidateNext.
+	NextCheck int
+	// NextViolation describes a rule broken by a proposed release.
+	NextViolation struct {
+		Check    NextCheck
+		Proposed Version
+		// Existing is the existing release the proposed version conflicts
+		// with. For SkippedVersions, it is the release preceding the proposed
+		// version.
+		Existing Version
+	}
+)
+
+const (
+	// NotGreatestOnLine means an existing release on the same line as the
+	// proposed version has higher precedence. See ValidateNext.
+	NotGreatestOnLine NextCheck = iota
+	// DuplicateRelease means an existing release has the same precedence as
+	// the proposed version, e.g. "1.2.3+build.2" when "1.2.3+build.1" exists.
+	DuplicateRelease
+	// SkippedVersions means the proposed version is not the next patch,
+	// minor or major version after the release preceding it, e.g. "1.2.5"
+	// when the preceding release is "1.2.3".
+	SkippedVersions
+	// PrereleaseAfterRelease means the proposed version is a prerelease of
+	// an existing release, e.g. "1.2.3-rc.1" when "1.2.3" exists.
+	PrereleaseAfterRelease
+)
+
+func (c NextCheck) String() string {
+	switch c {
+	case NotGreatestOnLine:
+		return "not greatest on line"
+	case DuplicateRelease:
+		return "duplicate release"
+	case SkippedVersions:
+		return "skipped versions"
+	case PrereleaseAfterRelease:
+		return "prerelease after release"
+	}
+	return fmt.Sprintf("NextCheck(%d)", int(c))
+}
+
+func (v NextViolation) String() string {
+	switch v.Check {
+	case NotGreatestOnLine:
+		return fmt.Sprintf("%s is lower than existing release %s", v.Proposed, v.Existing)
+	case DuplicateRelease:
+		return fmt.Sprintf("%s has the same precedence as existing release %s", v.Proposed, v.Existing)
+	case SkippedVersions:
+		next := nextVersions(v.Existing)
+		if v.Existing.IsPrerelease() {
+			return fmt.Sprintf("%s skips versions after %s; want one of %s, %s, %s or %s",
+				v.Proposed, v.Existing, next[0], next[1], next[2], next[3])
+		}
+		return fmt.Sprintf("%s skips versions after %s; want one of %s, %s or %s",
+			v.Proposed, v.Existing, next[1], next[2], next[3])
+	case PrereleaseAfterRelease:
+		return fmt.Sprintf("%s is a prerelease of existing release %s", v.Proposed, v.Existing)
+	}
+	return fmt.Sprintf("%s: %s conflicts with %s", v.Check, v.Proposed, v.Existing)
+}
+
+// ValidateNext checks that proposed is a valid next release, given the
+// existing releases in history, returning a violation for each rule it
+// breaks, or nil if it breaks none. The rules are:
+//
+//   - No release on the same line may have higher precedence. The line is
+//     the releases with the same major and minor versions, or if there are
+//     none, those with the same major version, or if there are none, all
+//     releases. So "1.2.4" may be released after "1.3.0", but "1.2.0" may
+//     not.
+//   - No release may have the same precedence, ignoring build metadata.
+//   - It must be the next patch, minor or major version after the release
+//     which precedes it, or a prerelease of one of those, or of the same
+//     version as a preceding prerelease.
+//   - It must not be a prerelease of an existing release.
+func ValidateNext(history VersionList, proposed Version) []NextViolation {
+	var violations []NextViolation
+	violate := func(check NextCheck, existing Version) {
+		violations = append(violations, NextViolation{Check: check, Proposed: proposed, Existing: existing})
+	}
+	if greatest, ok := releaseLine(history, proposed).Max(); ok && proposed.Less(greatest) {
+		// A prerelease of an existing release is reported only as such.
+		if greatest.IsPrerelease() || !greatest.MMPEqual(proposed) {
+			violate(NotGreatestOnLine, greatest)
+		}
+	}
+	var preceding *Version
+	for i, v := range history {
+		switch {
+		case v.Equals(proposed):
+			violate(DuplicateRelease, v)
+		case v.Less(proposed) && (preceding == nil || preceding.Less(v)):
+			preceding = &history[i]
+		}
+		if proposed.IsPrerelease() && !v.IsPrerelease() && v.MMPEqual(proposed) {
+			violate(PrereleaseAfterRelease, v)
+		}
+	}
+	if preceding != nil {
+		skipped := true
+		for _, next := range nextVersions(*preceding) {
+			skipped = skipped && !next.MMPEqual(proposed)
+		}
+		if skipped {
+			violate(SkippedVersions, *preceding)
+		}
+	}
+	return violations
+}
+
+// releaseLine returns the releases in history on the same line as v, as
+// described by ValidateNext.
+func releaseLine(history VersionList, v Version) VersionList {
+	sameMinor := history.FilterFunc(func(h Version) bool {
+		return h.MajorString() == v.MajorString() && h.MinorString() == v.MinorString()
+	})
+	if len(sameMinor) != 0 {
+		return sameMinor
+	}
+	sameMajor := history.FilterFunc(func(h Version) bool {
+		return h.MajorString() == v.MajorString()
+	})
+	if len(sameMajor) != 0 {
+		return sameMajor
+	}
+	return history
+}
+
+// nextVersions returns the major, minor and patch versions which may follow
+// v: its own, and the next patch, minor and major versions.
+func nextVersions(v Version) [4]Version {
+	v = v.MajorMinorPatch()
+	return [...]Version{v, v.IncrementPatch(), v.IncrementMinor(), v.IncrementMajor()}
+}
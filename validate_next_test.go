@@ -0,0 +1,59 @@
+package semv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateNext(t *testing.T) {
+	history := MustParseList("1.0.0", "1.1.0", "1.2.0", "1.2.1+build.1", "1.3.0-rc.1", "2.0.0")
+	tests := map[string]string{
+		"2.0.1":         "",
+		"2.1.0":         "",
+		"3.0.0-alpha":   "",
+		"1.2.2":         "",
+		"1.3.0":         "",
+		"1.3.0-rc.2":    "",
+		"1.4.0":         "",
+		"0.9.0":         "not greatest on line 2.0.0",
+		"1.1.5":         "skipped versions 1.1.0",
+		"1.3.0-beta":    "not greatest on line 1.3.0-rc.1",
+		"1.2.1+build.2": "duplicate release 1.2.1+build.1",
+		"2.0.0+build.2": "duplicate release 2.0.0",
+		"2.0.2":         "skipped versions 2.0.0",
+		"4.0.0":         "skipped versions 2.0.0",
+		"1.2.0-rc.1":    "not greatest on line 1.2.1+build.1, prerelease after release 1.2.0",
+		"2.0.0-rc.1":    "prerelease after release 2.0.0",
+	}
+	for proposed, expected := range tests {
+		var actual []string
+		for _, v := range ValidateNext(history, MustParse(proposed)) {
+			actual = append(actual, v.Check.String()+" "+v.Existing.String())
+		}
+		if strings.Join(actual, ", ") != expected {
+			t.Errorf("ValidateNext(%s): got %q; want %q", proposed, strings.Join(actual, ", "), expected)
+		}
+	}
+}
+
+func TestValidateNext_NoHistory(t *testing.T) {
+	if violations := ValidateNext(nil, MustParse("0.1.0")); violations != nil {
+		t.Errorf("got %v; want nil", violations)
+	}
+}
+
+func TestNextViolation_String(t *testing.T) {
+	history := MustParseList("1.2.3")
+	tests := map[string]string{
+		"1.2.5":      "1.2.5 skips versions after 1.2.3; want one of 1.2.4, 1.3.0 or 2.0.0",
+		"1.2.3-rc.1": "1.2.3-rc.1 is a prerelease of existing release 1.2.3",
+		"1.2.3+b":    "1.2.3+b has the same precedence as existing release 1.2.3",
+		"1.2.2":      "1.2.2 is lower than existing release 1.2.3",
+	}
+	for proposed, expected := range tests {
+		violations := ValidateNext(history, MustParse(proposed))
+		if len(violations) != 1 || violations[0].String() != expected {
+			t.Errorf("ValidateNext(%s): got %v; want [%s]", proposed, violations, expected)
+		}
+	}
+}
@@ -2,15 +2,25 @@ package semv
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 )
 
 type (
-	// Version is a semver version.
+	// Version is a semver version. If a major, minor or patch component is
+	// too large for an int, its field is math.MaxInt, and its exact value is
+	// available from Big, or MajorString, MinorString and PatchString.
 	Version struct {
 		Major, Minor, Patch      int
 		Pre, Meta, DefaultFormat string
+		// original is the exact text this version was parsed from by
+		// ParseLossless, or empty otherwise.
+		original string
+		// fingerprint is the fingerprint of the version when original was
+		// recorded, used to detect whether it has been changed since.
+		fingerprint uint64
+		// bigMajor, bigMinor and bigPatch are the digits of components too
+		// large for an int. See component.go.
+		bigMajor, bigMinor, bigPatch string
 	}
 	// VersionIncomplete is an error returned by ParseExactSemver2
 	// when a version is missing either minor or patch parts.
@@ -41,13 +51,13 @@ type (
 
 // NewVersion returns a new version with all fields set.
 func NewVersion(major, minor, patch int, pre, meta string) Version {
-	return Version{major, minor, patch, pre, meta, ""}
+	return Version{Major: major, Minor: minor, Patch: patch, Pre: pre, Meta: meta}
 }
 
 // NewMajorMinorPatch returns a new version with just the major, minor, and patch
 // fields set.
 func NewMajorMinorPatch(major, minor, patch int) Version {
-	return Version{major, minor, patch, "", "", ""}
+	return Version{Major: major, Minor: minor, Patch: patch}
 }
 
 func (err VersionIncomplete) Error() string {
@@ -118,10 +128,49 @@ func (v Version) Validate() error {
 //      Parse("1.2").String() == "1.2"
 //      Parse("1.2.3").String() == "1.2.3"
 //      Parse("1.2.3-beta").String() == "1.2.3-beta"
+//
+// If the version was parsed by ParseLossless and has not been modified since,
+// String returns exactly the text it was parsed from, as Original does.
 func (v Version) String() string {
+	if original := v.Original(); original != "" {
+		return original
+	}
 	return v.Format(v.DefaultFormat)
 }
 
+// Original returns the exact text this version was parsed from by
+// ParseLossless, including any "v" prefix and leading zeros. If the version
+// was not parsed by ParseLossless, or any of its fields have been changed
+// since, Original returns the empty string.
+func (v Version) Original() string {
+	if v.original == "" || v.fingerprint != v.fingerprintFields() {
+		return ""
+	}
+	return v.original
+}
+
+// fingerprintFields returns an FNV-1a hash of the fields which affect how
+// the version is formatted, so that Original can cheaply detect changes made
+// after parsing.
+func (v Version) fingerprintFields() uint64 {
+	const prime = 1099511628211
+	h := uint64(14695981039346656037)
+	for _, n := range [...]int{v.Major, v.Minor, v.Patch} {
+		for i := 0; i < 8; i++ {
+			h = (h ^ uint64(byte(n>>(8*i)))) * prime
+		}
+	}
+	for _, s := range [...]string{v.Pre, v.Meta, v.DefaultFormat, v.bigMajor, v.bigMinor, v.bigPatch} {
+		for i := 0; i < len(s); i++ {
+			h = (h ^ uint64(s[i])) * prime
+		}
+		// Separate the fields, so that moving a suffix of one field to the
+		// start of the next changes the hash.
+		h = (h ^ 0xff) * prime
+	}
+	return h
+}
+
 // MajorMinorPatch returns a new version with the prerelease and meta fields
 // set to the empty string, and major, minor, patch equalling the
 // major, minor, patch of the version it was invoked on.
@@ -149,57 +198,85 @@ func (v Version) IsPrerelease() bool {
 //
 // See other constants in this library for more. The empty string is treated
 // equivalently to the format string "M.m.p-?+?".
+//
+// Because these characters are replaced wherever they appear, they cannot be
+// used literally, so a format string containing a '%' is instead interpreted
+// using the following format language, in which only verbs are replaced:
+//
+//     %M, %m, %p    the major, minor and patch numbers
+//     %r, %b        the prerelease and build metadata, without '-' or '+'
+//     %[n]r, %[n]b  the nth (1-based) dot-separated identifier of the
+//                   prerelease or build metadata
+//     %%            a literal '%'
+//     \c            the character c literally, e.g. \{
+//     {...}         a conditional section, which is omitted unless every
+//                   verb directly within it is non-zero or non-empty
+//
+// A width of up to MaxFormatWidth may follow the '%', optionally preceded by
+// a '0', to pad the value with spaces or zeros, e.g. "%03p". For example, "v%M.%m{.%p}{-%r}" formats
+// 1.2.0-beta as "v1.2-beta", and 1.2.3 as "v1.2.3". Malformed formats are
+// reported by ValidateFormat; Format prints them as "%!(error)", like package
+// fmt does.
 func (v Version) Format(format string) string {
+	var buf [64]byte
+	return string(v.AppendFormat(buf[:0], format))
+}
+
+// AppendFormat is like Format, except that it appends the formatted version
+// to dst and returns the extended buffer. It does not allocate, unless dst
+// is not large enough, or the format is malformed.
+func (v Version) AppendFormat(dst []byte, format string) []byte {
 	if format == "" {
 		format = Complete
 	}
-	replacements := map[string]interface{}{
-		Major: v.Major,
-		Minor: v.Minor,
-		Patch: v.Patch,
-	}
-	formatted := replaceAll(format, replacements)
-	if v.Pre != "" {
-		formatted = strings.Replace(formatted, Pre, PreDelim+v.Pre, -1)
-	} else {
-		formatted = strings.Replace(formatted, Pre, "", -1)
+	if isFormatLanguage(format) {
+		start := len(dst)
+		formatted, err := v.appendFormat(dst, format)
+		if err != nil {
+			formatted = append(formatted[:start], "%!("...)
+			formatted = append(formatted, err.Error()...)
+			formatted = append(formatted, ')')
+		}
+		return formatted
 	}
-	if v.Meta != "" {
-		formatted = strings.Replace(formatted, Meta, MetaDelim+v.Meta, -1)
-	} else {
-		formatted = strings.Replace(formatted, Meta, "", -1)
+	for i := 0; i < len(format); i++ {
+		switch c := format[i]; {
+		case c == Major[0]:
+			dst = appendComponent(dst, v.Major, v.bigMajor)
+		case c == Minor[0]:
+			dst = appendComponent(dst, v.Minor, v.bigMinor)
+		case c == Patch[0]:
+			dst = appendComponent(dst, v.Patch, v.bigPatch)
+		case (c == PreDelim[0] || c == MetaDelim[0]) && i+1 < len(format) &&
+			(format[i+1] == Pre[1] || format[i+1] == PreRaw[1]):
+			field := v.Pre
+			if c == MetaDelim[0] {
+				field = v.Meta
+			}
+			if format[i+1] == Pre[1] && field != "" {
+				dst = append(dst, c)
+			}
+			dst = append(dst, field...)
+			i++
+		default:
+			dst = append(dst, c)
+		}
 	}
-	formatted = strings.Replace(formatted, PreRaw, v.Pre, -1)
-	formatted = strings.Replace(formatted, MetaRaw, v.Meta, -1)
-	return formatted
+	return dst
 }
 
 // SetFormat sets the default format string to use when calling String()
-// see Format for acceptable format strings.
-func (v Version) SetFormat(format string) {
+// see Format for acceptable format strings. To change only the number of
+// components String prints, use WithPrecision.
+func (v *Version) SetFormat(format string) {
 	v.DefaultFormat = format
 }
 
 // Less returns true if the version it is invoked on is less than the version
 // passed in, according to the precendence rules in semver 2.0.0
 func (v Version) Less(than Version) bool {
-	if v.Major < than.Major {
-		return true
-	}
-	if v.Major > than.Major {
-		return false
-	}
-	if v.Minor < than.Minor {
-		return true
-	}
-	if v.Minor > than.Minor {
-		return false
-	}
-	if v.Patch < than.Patch {
-		return true
-	}
-	if v.Patch > than.Patch {
-		return false
+	if c := v.compareMMP(than); c != 0 {
+		return c < 0
 	}
 	if v.Pre != "" && than.Pre == "" {
 		return true
@@ -207,32 +284,59 @@ func (v Version) Less(than Version) bool {
 	if v.Pre == "" && than.Pre != "" {
 		return false
 	}
-	vPreParts := v.PreComponents()
-	thanPreParts := than.PreComponents()
-	for i := range thanPreParts {
-		if vInt, err := strconv.Atoi(vPreParts[i]); err == nil {
-			if tInt, err := strconv.Atoi(thanPreParts[i]); err == nil {
-				if vInt < tInt {
-					return true
-				}
-				if vInt > tInt {
-					return false
-				}
-			} else {
-				return true
-			}
-		}
-		if vPreParts[i] < thanPreParts[i] {
-			return true
+	vRest, thanRest := v.Pre, than.Pre
+	for {
+		vID, vNext, vMore := strings.Cut(vRest, ".")
+		thanID, thanNext, thanMore := strings.Cut(thanRest, ".")
+		if c := comparePreIdentifiers(vID, thanID); c != 0 {
+			return c < 0
 		}
-		if vPreParts[i] > thanPreParts[i] {
+		if !thanMore {
 			return false
 		}
-		if len(vPreParts) == i+1 && len(thanPreParts) > len(vPreParts) {
+		if !vMore {
 			return true
 		}
+		vRest, thanRest = vNext, thanNext
+	}
+}
+
+// comparePreIdentifiers compares a single identifier from the prerelease
+// field of each of two versions, returning -1, 0 or 1. Numeric identifiers
+// have lower precedence than alphanumeric ones, whichever side they are on.
+func comparePreIdentifiers(a, b string) int {
+	aNumeric, bNumeric := isNumeric(a), isNumeric(b)
+	switch {
+	case aNumeric && bNumeric:
+		if c := compareNumeric(a, b); c != 0 {
+			return c
+		}
+	case aNumeric:
+		return -1
+	case bNumeric:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// isNumeric returns true if s is a non-empty string of decimal digits.
+func isNumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// compareNumeric compares two strings of decimal digits numerically, however
+// long they are, returning -1, 0 or 1.
+func compareNumeric(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return compareInts(len(a), len(b))
 	}
-	return false
+	return strings.Compare(a, b)
 }
 
 // MMPLess returns true if the version it is invoked on's major, minor, patch
@@ -259,6 +363,16 @@ func (v Version) Equals(other Version) bool {
 	return !v.Less(other) && !other.Less(v)
 }
 
+// ExactEquals returns true if the versions have identical fields, including
+// their metadata, and String returns the same text for each of them. Unlike
+// Equals, which follows semver precedence rules, ExactEquals distinguishes
+// "1.0.0+a" from "1.0.0+b", "1.0" from "1.0.0", and "v1.0.0" from "1.0.0" when
+// parsed by ParseLossless.
+func (v Version) ExactEquals(other Version) bool {
+	return v.Major == other.Major && v.Minor == other.Minor && v.Patch == other.Patch &&
+		v.Pre == other.Pre && v.Meta == other.Meta && v.String() == other.String()
+}
+
 // ValueEquals works on Version pointers, and checks that their values
 // are equal. Also returns true if both are nil, and obviously false if
 // one is nil and ther other one isn't.
@@ -283,23 +397,23 @@ func (v Version) Satisfies(r Range) bool {
 // IncrementMajor returns a new Version with the major field incremented by 1
 // and minor and patch set to zero.
 func (v Version) IncrementMajor() Version {
-	v.Major++
-	v.Minor = 0
-	v.Patch = 0
+	v.Major, v.bigMajor = incrementComponent(v.Major, v.bigMajor)
+	v.Minor, v.bigMinor = 0, ""
+	v.Patch, v.bigPatch = 0, ""
 	return v
 }
 
 // IncrementMinor returns a new Version with the minor field incremented by 1
 // and the patch set to zero.
 func (v Version) IncrementMinor() Version {
-	v.Minor++
-	v.Patch = 0
+	v.Minor, v.bigMinor = incrementComponent(v.Minor, v.bigMinor)
+	v.Patch, v.bigPatch = 0, ""
 	return v
 }
 
 // IncrementPatch returns a new Version with the patch field incremented by 1.
 func (v Version) IncrementPatch() Version {
-	v.Patch++
+	v.Patch, v.bigPatch = incrementComponent(v.Patch, v.bigPatch)
 	return v
 }
 
@@ -316,9 +430,11 @@ func (v Version) SetMeta(s string) Version {
 	return v
 }
 
-// MarshalYAML allows sensible YAML marshalling by gopkg.in/yaml.v2
+// MarshalYAML allows sensible YAML marshalling by gopkg.in/yaml.v2. Like
+// MarshalJSON, it writes the version without the exact text recorded by
+// ParseLossless, so that it can be read back by UnmarshalYAML.
 func (v Version) MarshalYAML() (interface{}, error) {
-	return v.String(), nil
+	return v.Format(v.DefaultFormat), nil
 }
 
 // UnmarshalYAML allows sensible YAML unmarshalling by gopkg.in/yaml.v2
@@ -331,9 +447,12 @@ func (v *Version) UnmarshalYAML(f func(interface{}) error) (err error) {
 	return
 }
 
-// MarshalJSON marshals this version to a JSON string.
+// MarshalJSON marshals this version to a JSON string. Versions parsed by
+// ParseLossless are written in their DefaultFormat rather than the exact text
+// they were parsed from, which may have a "v" prefix or leading zeros that
+// UnmarshalJSON would reject.
 func (v Version) MarshalJSON() ([]byte, error) {
-	return []byte(`"` + v.String() + `"`), nil
+	return []byte(`"` + v.Format(v.DefaultFormat) + `"`), nil
 }
 
 // UnmarshalJSON unmarshals from a JSON string.
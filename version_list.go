@@ -72,6 +72,15 @@ func (vl VersionList) Sorted() VersionList {
 	return newVL
 }
 
+// SortedExact is similar to Sorted, except that versions of equal precedence
+// are ordered by their metadata, as by ExactCompare, so the result does not
+// depend on the original order of the list.
+func (vl VersionList) SortedExact() VersionList {
+	newVL := vl.Clone()
+	sort.Slice(newVL, func(i, j int) bool { return ExactCompare(newVL[i], newVL[j]) < 0 })
+	return newVL
+}
+
 // Clone returns an itemwise copy of this VersionList
 func (vl VersionList) Clone() VersionList {
 	newVL := make(VersionList, len(vl))
@@ -84,7 +93,7 @@ func (vl VersionList) Clone() VersionList {
 // SortedDesc is similar to Sorted, except the returned copy of the list is sorted in
 // the opposite direction.
 func (vl VersionList) SortedDesc() VersionList {
-	newVL := vl.Sorted()
+	newVL := vl.Clone()
 	sort.Sort(sort.Reverse(newVL))
 	return newVL
 }
@@ -92,12 +101,184 @@ func (vl VersionList) SortedDesc() VersionList {
 // GreatestSatisfying returns the greatest (highest) version contained in the
 // VersionList, which satisfies the passed Range. If none are found that satisfy
 // the range, the second return value is false, otherwise it is true.
+//
+// GreatestSatisfying scans the whole list on every call. If you need to query
+// the same versions many times, a VersionSet will be much faster.
 func (vl VersionList) GreatestSatisfying(r Range) (Version, bool) {
-	sortedCopy := vl.SortedDesc()
-	for _, v := range sortedCopy {
-		if r.SatisfiedBy(v) {
-			return v, true
+	var greatest Version
+	found := false
+	for _, v := range vl {
+		if r.SatisfiedBy(v) && (!found || greatest.Less(v)) {
+			greatest, found = v, true
+		}
+	}
+	return greatest, found
+}
+
+// LeastSatisfying returns the least (lowest) version contained in the
+// VersionList, which satisfies the passed Range. If none are found that satisfy
+// the range, the second return value is false, otherwise it is true.
+func (vl VersionList) LeastSatisfying(r Range) (Version, bool) {
+	var least Version
+	found := false
+	for _, v := range vl {
+		if r.SatisfiedBy(v) && (!found || v.Less(least)) {
+			least, found = v, true
+		}
+	}
+	return least, found
+}
+
+// Filter returns a new VersionList containing only the versions which satisfy
+// the passed Range, in their original order.
+func (vl VersionList) Filter(r Range) VersionList {
+	return vl.FilterFunc(r.SatisfiedBy)
+}
+
+// FilterFunc returns a new VersionList containing only the versions for which
+// f returns true, in their original order.
+func (vl VersionList) FilterFunc(f func(Version) bool) VersionList {
+	newVL := VersionList{}
+	for _, v := range vl {
+		if f(v) {
+			newVL = append(newVL, v)
+		}
+	}
+	return newVL
+}
+
+// Min returns the lowest version in the list. If the list is empty, the
+// second return value is false.
+func (vl VersionList) Min() (Version, bool) {
+	if len(vl) == 0 {
+		return Version{}, false
+	}
+	min := vl[0]
+	for _, v := range vl[1:] {
+		if v.Less(min) {
+			min = v
 		}
 	}
-	return Version{}, false
+	return min, true
+}
+
+// Max returns the highest version in the list. If the list is empty, the
+// second return value is false.
+func (vl VersionList) Max() (Version, bool) {
+	if len(vl) == 0 {
+		return Version{}, false
+	}
+	max := vl[0]
+	for _, v := range vl[1:] {
+		if max.Less(v) {
+			max = v
+		}
+	}
+	return max, true
+}
+
+// Stable returns a new VersionList containing only the versions which are not
+// prereleases, in their original order.
+func (vl VersionList) Stable() VersionList {
+	return vl.FilterFunc(func(v Version) bool { return !v.IsPrerelease() })
+}
+
+// Prereleases returns a new VersionList containing only the prerelease
+// versions, in their original order.
+func (vl VersionList) Prereleases() VersionList {
+	return vl.FilterFunc(Version.IsPrerelease)
+}
+
+// Dedupe returns a new VersionList with versions of equal precedence removed,
+// keeping only the first of each, in their original order. Since precedence
+// ignores metadata, "1.0.0+a" and "1.0.0+b" are duplicates. To only remove
+// exact duplicates, use DedupeExact.
+func (vl VersionList) Dedupe() VersionList {
+	return vl.dedupe(Version.Less)
+}
+
+// DedupeExact is similar to Dedupe, except that versions are only removed if
+// their metadata is equal, as well as their precedence.
+func (vl VersionList) DedupeExact() VersionList {
+	return vl.dedupe(func(a, b Version) bool {
+		return a.Less(b) || a.Equals(b) && a.Meta < b.Meta
+	})
+}
+
+// dedupe removes all but the first of each run of versions which are equal
+// according to less, preserving the order of the versions which remain. It
+// works on a stably sorted index, so that it is O(n log n) rather than
+// comparing every pair of versions.
+func (vl VersionList) dedupe(less func(a, b Version) bool) VersionList {
+	order := make([]int, len(vl))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return less(vl[order[i]], vl[order[j]]) })
+	keep := make([]bool, len(vl))
+	for i, index := range order {
+		keep[index] = i == 0 || less(vl[order[i-1]], vl[index])
+	}
+	newVL := VersionList{}
+	for i, v := range vl {
+		if keep[i] {
+			newVL = append(newVL, v)
+		}
+	}
+	return newVL
+}
+
+// GroupByMajor returns the versions in this list grouped by major version.
+// The groups are ordered from lowest to highest major version, and the
+// versions within each group are sorted from lowest to highest.
+func (vl VersionList) GroupByMajor() []VersionList {
+	return vl.groupBy(func(a, b Version) bool {
+		return compareComponents(a.Major, a.bigMajor, b.Major, b.bigMajor) == 0
+	})
+}
+
+// GroupByMinor is similar to GroupByMajor, except that versions are grouped
+// by their major and minor versions together, so that each group represents
+// a single minor release line.
+func (vl VersionList) GroupByMinor() []VersionList {
+	return vl.groupBy(func(a, b Version) bool {
+		return compareComponents(a.Major, a.bigMajor, b.Major, b.bigMajor) == 0 &&
+			compareComponents(a.Minor, a.bigMinor, b.Minor, b.bigMinor) == 0
+	})
+}
+
+// groupBy splits a sorted copy of this list into runs of versions for which
+// sameGroup returns true for adjacent elements.
+func (vl VersionList) groupBy(sameGroup func(a, b Version) bool) []VersionList {
+	var groups []VersionList
+	for _, v := range vl.Sorted() {
+		if n := len(groups); n != 0 && sameGroup(groups[n-1][0], v) {
+			groups[n-1] = append(groups[n-1], v)
+			continue
+		}
+		groups = append(groups, VersionList{v})
+	}
+	return groups
+}
+
+// LatestPerMajor returns the highest version of each major version in the
+// list, from lowest to highest. Prereleases are included; to find the latest
+// stable release of each major version, use Stable().LatestPerMajor().
+func (vl VersionList) LatestPerMajor() VersionList {
+	return latestPerGroup(vl.GroupByMajor())
+}
+
+// LatestPerMinor returns the highest version of each minor release line in
+// the list, from lowest to highest. This is the latest patch of every minor
+// version. Like LatestPerMajor, prereleases are included.
+func (vl VersionList) LatestPerMinor() VersionList {
+	return latestPerGroup(vl.GroupByMinor())
+}
+
+func latestPerGroup(groups []VersionList) VersionList {
+	newVL := make(VersionList, len(groups))
+	for i, g := range groups {
+		newVL[i] = g[len(g)-1]
+	}
+	return newVL
 }
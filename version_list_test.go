@@ -127,3 +127,114 @@ func TestGreatestSatisfying(t *testing.T) {
 		}
 	}
 }
+
+func assertVersionList(t *testing.T, name string, actual VersionList, expected ...string) {
+	if len(actual) != len(expected) {
+		t.Errorf("%s gave %v; want %v", name, actual, expected)
+		return
+	}
+	for i, v := range actual {
+		if v.String() != expected[i] {
+			t.Errorf("%s gave %v; want %v", name, actual, expected)
+			return
+		}
+	}
+}
+
+var rangeToLeastSatisfyingVersion = map[string]string{
+	"0.0.0":       "0.0.0",
+	">0.0.0":      "0.0.1",
+	"<1.1.9":      "0.0.0",
+	">=1.1.3":     "1.1.3",
+	"~0.1.5":      "0.1.10",
+	"~1.1.0-beta": "1.1.0-beta",
+	"^1.0.0":      "1.0.0",
+	"^3.0.0":      "3.0.0",
+}
+
+func TestLeastSatisfying(t *testing.T) {
+	vl := newRandomisedVersionList()
+	for rangeString, versionString := range rangeToLeastSatisfyingVersion {
+		r := MustParseRange(rangeString)
+		actual, ok := vl.LeastSatisfying(r)
+		if !ok {
+			t.Errorf("expected to find a version satisfying %q", r)
+			continue
+		}
+		if actual.String() != versionString {
+			t.Errorf("got least version %q satisfying %q; expected %q", actual, r, versionString)
+		}
+	}
+	if _, ok := vl.LeastSatisfying(MustParseRange(">6.0.0")); ok {
+		t.Errorf("expected no version to satisfy >6.0.0")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	vl := MustParseList("1.2.0", "2.0.0", "1.0.0", "1.5.0-beta", "1.5.0")
+	assertVersionList(t, "Filter", vl.Filter(MustParseRange("^1.0.0")), "1.2.0", "1.0.0", "1.5.0")
+	assertVersionList(t, "FilterFunc", vl.FilterFunc(func(v Version) bool { return v.Minor == 0 }),
+		"2.0.0", "1.0.0")
+	assertVersionList(t, "Filter", vl.Filter(MustParseRange(">3.0.0")))
+}
+
+func TestMinMax(t *testing.T) {
+	vl := newRandomisedVersionList()
+	if min, ok := vl.Min(); !ok || min.String() != "0.0.0" {
+		t.Errorf("got min %q; want 0.0.0", min)
+	}
+	if max, ok := vl.Max(); !ok || max.String() != "5.8.0" {
+		t.Errorf("got max %q; want 5.8.0", max)
+	}
+	if _, ok := (VersionList{}).Min(); ok {
+		t.Errorf("expected no min for empty list")
+	}
+	if _, ok := (VersionList{}).Max(); ok {
+		t.Errorf("expected no max for empty list")
+	}
+}
+
+func TestStableAndPrereleases(t *testing.T) {
+	vl := MustParseList("1.0.0-beta", "1.0.0", "1.1.0-rc.1", "1.1.0")
+	assertVersionList(t, "Stable", vl.Stable(), "1.0.0", "1.1.0")
+	assertVersionList(t, "Prereleases", vl.Prereleases(), "1.0.0-beta", "1.1.0-rc.1")
+}
+
+func TestDedupe(t *testing.T) {
+	vl := MustParseList("1.0.0+a", "1.1.0", "1.0.0+b", "1.0.0+a", "1.1.0")
+	assertVersionList(t, "Dedupe", vl.Dedupe(), "1.0.0+a", "1.1.0")
+	assertVersionList(t, "DedupeExact", vl.DedupeExact(), "1.0.0+a", "1.1.0", "1.0.0+b")
+	vl = MustParseList("2.0.0", "1.0", "1.0.0-rc.1+x", "2.0.0+b", "1.0.0", "0.9.0", "1.0.0-rc.1")
+	assertVersionList(t, "Dedupe unsorted", vl.Dedupe(), "2.0.0", "1.0", "1.0.0-rc.1+x", "0.9.0")
+	assertVersionList(t, "DedupeExact unsorted", vl.DedupeExact(), "2.0.0", "1.0", "1.0.0-rc.1+x", "2.0.0+b", "0.9.0", "1.0.0-rc.1")
+	if d := (VersionList{}).Dedupe(); len(d) != 0 {
+		t.Errorf("got %s; want an empty list", d)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	vl := MustParseList("2.1.0", "1.0.0", "1.1.1", "2.0.0", "1.1.0", "1.0.1-beta")
+	majors := vl.GroupByMajor()
+	if len(majors) != 2 {
+		t.Fatalf("got %d major groups; want 2", len(majors))
+	}
+	assertVersionList(t, "GroupByMajor", majors[0], "1.0.0", "1.0.1-beta", "1.1.0", "1.1.1")
+	assertVersionList(t, "GroupByMajor", majors[1], "2.0.0", "2.1.0")
+	minors := vl.GroupByMinor()
+	if len(minors) != 4 {
+		t.Fatalf("got %d minor groups; want 4", len(minors))
+	}
+	assertVersionList(t, "GroupByMinor", minors[0], "1.0.0", "1.0.1-beta")
+	assertVersionList(t, "GroupByMinor", minors[3], "2.1.0")
+}
+
+func TestLatestPer(t *testing.T) {
+	vl := newRandomisedVersionList()
+	assertVersionList(t, "LatestPerMajor", vl.LatestPerMajor(), "0.3.1", "1.2.1", "2.1.1", "3.5.6", "5.8.0")
+	assertVersionList(t, "LatestPerMinor", vl.LatestPerMinor(),
+		"0.0.3", "0.1.12-beta", "0.2.35", "0.3.1", "1.0.2", "1.1.9", "1.2.1", "2.0.1", "2.1.1",
+		"3.0.0", "3.5.6", "5.8.0")
+	assertVersionList(t, "Stable().LatestPerMinor", vl.Stable().LatestPerMinor(),
+		"0.0.3", "0.1.11", "0.2.35", "0.3.1", "1.0.2", "1.1.9", "1.2.1", "2.0.1", "2.1.1",
+		"3.0.0", "3.5.6", "5.8.0")
+}
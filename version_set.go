@@ -0,0 +1,182 @@
+package semv
+
+import "sort"
+
+// VersionSet is an immutable set of versions, kept sorted from lowest to
+// highest. Because it is always sorted, range queries such as
+// GreatestSatisfying use binary search rather than sorting or scanning the
+// whole set, which makes VersionSet preferable to VersionList when the same
+// versions are queried many times.
+//
+// Versions of equal precedence but different metadata, e.g. "1.0.0+a" and
+// "1.0.0+b", are distinct members of the set, ordered by their metadata.
+//
+// Methods which modify the set, such as Insert and Remove, return a new set,
+// leaving the original unchanged, so a VersionSet is safe for concurrent use.
+// The zero VersionSet is an empty set, ready to use.
+type VersionSet struct {
+	versions VersionList
+}
+
+// NewVersionSet returns a new VersionSet containing the versions passed in,
+// with any exact duplicates removed.
+func NewVersionSet(versions ...Version) VersionSet {
+	vl := VersionList(versions).Clone()
+	sort.Slice(vl, func(i, j int) bool { return setLess(vl[i], vl[j]) })
+	return VersionSet{dedupeSorted(vl)}
+}
+
+// setLess orders versions by precedence, and then by metadata, so that
+// distinct versions of equal precedence have a deterministic order.
+func setLess(a, b Version) bool {
+	if a.Less(b) {
+		return true
+	}
+	return !b.Less(a) && a.Meta < b.Meta
+}
+
+// setEqual returns true if neither version is setLess than the other.
+func setEqual(a, b Version) bool {
+	return !setLess(a, b) && !setLess(b, a)
+}
+
+// dedupeSorted removes adjacent setEqual versions in place.
+func dedupeSorted(vl VersionList) VersionList {
+	if len(vl) == 0 {
+		return vl
+	}
+	out := vl[:1]
+	for _, v := range vl[1:] {
+		if !setEqual(out[len(out)-1], v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Len returns the number of versions in the set.
+func (s VersionSet) Len() int { return len(s.versions) }
+
+// List returns a copy of the versions in this set, from lowest to highest.
+func (s VersionSet) List() VersionList { return s.versions.Clone() }
+
+// search returns the index at which v is, or would be, in the set.
+func (s VersionSet) search(v Version) int {
+	return sort.Search(len(s.versions), func(i int) bool {
+		return !setLess(s.versions[i], v)
+	})
+}
+
+// Contains returns true if the set contains a version exactly matching v,
+// including its metadata.
+func (s VersionSet) Contains(v Version) bool {
+	i := s.search(v)
+	return i < len(s.versions) && setEqual(s.versions[i], v)
+}
+
+// Insert returns a new set containing the versions in this set, plus those
+// passed in.
+func (s VersionSet) Insert(versions ...Version) VersionSet {
+	return s.Union(NewVersionSet(versions...))
+}
+
+// Remove returns a new set containing the versions in this set, except for
+// those exactly matching any of the versions passed in.
+func (s VersionSet) Remove(versions ...Version) VersionSet {
+	remove := NewVersionSet(versions...)
+	out := make(VersionList, 0, len(s.versions))
+	for _, v := range s.versions {
+		if !remove.Contains(v) {
+			out = append(out, v)
+		}
+	}
+	return VersionSet{out}
+}
+
+// Union returns a new set containing all the versions in this set, and all
+// the versions in the set passed in. It merges the two sorted sets in linear
+// time.
+func (s VersionSet) Union(other VersionSet) VersionSet {
+	a, b := s.versions, other.versions
+	out := make(VersionList, 0, len(a)+len(b))
+	for len(a) != 0 && len(b) != 0 {
+		switch {
+		case setLess(a[0], b[0]):
+			out, a = append(out, a[0]), a[1:]
+		case setLess(b[0], a[0]):
+			out, b = append(out, b[0]), b[1:]
+		default:
+			out, a, b = append(out, a[0]), a[1:], b[1:]
+		}
+	}
+	out = append(append(out, a...), b...)
+	return VersionSet{out}
+}
+
+// bounds returns the indexes of the first version in the set within the
+// bounds of r, and of the first version after them. Every version
+// satisfying r lies in between, although prerelease versions in between
+// may not satisfy r.
+func (s VersionSet) bounds(r Range) (int, int) {
+	r = r.Normalize()
+	lo, hi := 0, len(s.versions)
+	if min, inclusive := r.lower(); min != nil {
+		lo = sort.Search(len(s.versions), func(i int) bool {
+			if inclusive {
+				return !s.versions[i].Less(*min)
+			}
+			return min.Less(s.versions[i])
+		})
+	}
+	if max, inclusive := r.upper(); max != nil {
+		hi = sort.Search(len(s.versions), func(i int) bool {
+			if inclusive {
+				return max.Less(s.versions[i])
+			}
+			return !s.versions[i].Less(*max)
+		})
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// GreatestSatisfying returns the greatest (highest) version in the set which
+// satisfies the passed Range. If none are found that satisfy the range, the
+// second return value is false, otherwise it is true.
+func (s VersionSet) GreatestSatisfying(r Range) (Version, bool) {
+	lo, hi := s.bounds(r)
+	for i := hi - 1; i >= lo; i-- {
+		if r.SatisfiedBy(s.versions[i]) {
+			return s.versions[i], true
+		}
+	}
+	return Version{}, false
+}
+
+// LeastSatisfying returns the least (lowest) version in the set which
+// satisfies the passed Range. If none are found that satisfy the range, the
+// second return value is false, otherwise it is true.
+func (s VersionSet) LeastSatisfying(r Range) (Version, bool) {
+	lo, hi := s.bounds(r)
+	for i := lo; i < hi; i++ {
+		if r.SatisfiedBy(s.versions[i]) {
+			return s.versions[i], true
+		}
+	}
+	return Version{}, false
+}
+
+// AllSatisfying returns all the versions in the set which satisfy the passed
+// Range, from lowest to highest.
+func (s VersionSet) AllSatisfying(r Range) VersionList {
+	lo, hi := s.bounds(r)
+	out := VersionList{}
+	for _, v := range s.versions[lo:hi] {
+		if r.SatisfiedBy(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,154 @@
+package semv
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNewVersionSet(t *testing.T) {
+	s := NewVersionSet(MustParseList("1.1.0", "1.0.0+b", "1.0.0", "1.1.0", "1.0.0+a", "0.1.0")...)
+	assertVersionList(t, "NewVersionSet", s.List(), "0.1.0", "1.0.0", "1.0.0+a", "1.0.0+b", "1.1.0")
+	if s.Len() != 5 {
+		t.Errorf("got Len() == %d; want 5", s.Len())
+	}
+	if !s.Contains(MustParse("1.0.0+a")) || s.Contains(MustParse("1.0.0+c")) {
+		t.Errorf("Contains does not respect metadata")
+	}
+}
+
+func TestVersionSetSatisfying(t *testing.T) {
+	s := NewVersionSet(newRandomisedVersionList()...)
+	for rangeString, versionString := range rangeToGreatestSatisfyingVersion {
+		r := MustParseRange(rangeString)
+		actual, ok := s.GreatestSatisfying(r)
+		if !ok || actual.String() != versionString {
+			t.Errorf("got greatest version %q satisfying %q; expected %q", actual, r, versionString)
+		}
+	}
+	for rangeString, versionString := range rangeToLeastSatisfyingVersion {
+		r := MustParseRange(rangeString)
+		actual, ok := s.LeastSatisfying(r)
+		if !ok || actual.String() != versionString {
+			t.Errorf("got least version %q satisfying %q; expected %q", actual, r, versionString)
+		}
+	}
+	vl := newOrderedVersionList()
+	for rangeString := range rangeToGreatestSatisfyingVersion {
+		r := MustParseRange(rangeString)
+		expected := VersionList{}
+		for _, v := range vl {
+			if r.SatisfiedBy(v) {
+				expected = append(expected, v)
+			}
+		}
+		actual := s.AllSatisfying(r)
+		if len(actual) != len(expected) {
+			t.Errorf("AllSatisfying(%q) gave %v; want %v", r, actual, expected)
+		}
+	}
+	for _, rs := range []string{">6.0.0", "<0.0.0", ">2.0.0 <1.0.0"} {
+		if v, ok := s.GreatestSatisfying(MustParseRange(rs)); ok {
+			t.Errorf("expected no version to satisfy %q; got %q", rs, v)
+		}
+		if v, ok := s.LeastSatisfying(MustParseRange(rs)); ok {
+			t.Errorf("expected no version to satisfy %q; got %q", rs, v)
+		}
+	}
+}
+
+func TestVersionSetInsertRemove(t *testing.T) {
+	original := NewVersionSet(MustParseList("1.0.0", "2.0.0")...)
+	inserted := original.Insert(MustParseList("1.5.0", "0.5.0", "2.0.0")...)
+	assertVersionList(t, "Insert", inserted.List(), "0.5.0", "1.0.0", "1.5.0", "2.0.0")
+	removed := inserted.Remove(MustParseList("1.0.0", "3.0.0")...)
+	assertVersionList(t, "Remove", removed.List(), "0.5.0", "1.5.0", "2.0.0")
+	assertVersionList(t, "original", original.List(), "1.0.0", "2.0.0")
+}
+
+func TestVersionSetUnion(t *testing.T) {
+	a := NewVersionSet(MustParseList("1.0.0", "1.2.0", "3.0.0")...)
+	b := NewVersionSet(MustParseList("0.1.0", "1.2.0", "2.0.0", "4.0.0")...)
+	assertVersionList(t, "Union", a.Union(b).List(), "0.1.0", "1.0.0", "1.2.0", "2.0.0", "3.0.0", "4.0.0")
+	assertVersionList(t, "Union", VersionSet{}.Union(a).List(), "1.0.0", "1.2.0", "3.0.0")
+}
+
+// newLargeVersionList returns a shuffled list of 10,000 distinct versions.
+func newLargeVersionList() VersionList {
+	vl := make(VersionList, 0, 10000)
+	for major := 0; major < 10; major++ {
+		for minor := 0; minor < 50; minor++ {
+			for patch := 0; patch < 20; patch++ {
+				vl = append(vl, MustParse(fmt.Sprintf("%d.%d.%d", major, minor, patch)))
+			}
+		}
+	}
+	for i := range vl {
+		j := (i * 7919) % len(vl)
+		vl.Swap(i, j)
+	}
+	return vl
+}
+
+var benchmarkRanges = []Range{
+	MustParseRange("^3.0.0"),
+	MustParseRange("~7.12.0"),
+	MustParseRange("<1.0.0"),
+	MustParseRange(">=9.49.0"),
+}
+
+func BenchmarkVersionList_GreatestSatisfying(b *testing.B) {
+	vl := newLargeVersionList()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vl.GreatestSatisfying(benchmarkRanges[i%len(benchmarkRanges)])
+	}
+}
+
+func BenchmarkVersionSet_GreatestSatisfying(b *testing.B) {
+	s := NewVersionSet(newLargeVersionList()...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.GreatestSatisfying(benchmarkRanges[i%len(benchmarkRanges)])
+	}
+}
+
+func BenchmarkVersionList_LeastSatisfying(b *testing.B) {
+	vl := newLargeVersionList()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vl.LeastSatisfying(benchmarkRanges[i%len(benchmarkRanges)])
+	}
+}
+
+func BenchmarkVersionSet_LeastSatisfying(b *testing.B) {
+	s := NewVersionSet(newLargeVersionList()...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.LeastSatisfying(benchmarkRanges[i%len(benchmarkRanges)])
+	}
+}
+
+func BenchmarkVersionList_Filter(b *testing.B) {
+	vl := newLargeVersionList()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vl.Filter(benchmarkRanges[i%len(benchmarkRanges)])
+	}
+}
+
+func BenchmarkVersionSet_AllSatisfying(b *testing.B) {
+	s := NewVersionSet(newLargeVersionList()...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.AllSatisfying(benchmarkRanges[i%len(benchmarkRanges)])
+	}
+}
+
+func BenchmarkVersionSet_Insert(b *testing.B) {
+	s := NewVersionSet(newLargeVersionList()...)
+	v := MustParse("5.25.100")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Insert(v)
+	}
+}
@@ -8,22 +8,30 @@ import (
 	"testing"
 )
 
+// testVersion returns a version with all fields set, as if it had been
+// parsed in the given format.
+func testVersion(major, minor, patch int, pre, meta, format string) Version {
+	v := NewVersion(major, minor, patch, pre, meta)
+	v.SetFormat(format)
+	return v
+}
+
 // reversibleParseVersions are versions that when parsed, and
 // String() is called on the resulting version, the original
 // input string is returned.
 var reversibleParseVersions = map[string]Version{
-	"1":                          {1, 0, 0, "", "", Major},
-	"1.2":                        {1, 2, 0, "", "", MajorMinor},
-	"1.2.3":                      {1, 2, 3, "", "", MajorMinorPatch},
-	"1.2.3-beta.1":               {1, 2, 3, "beta.1", "", MMPPre},
-	"1.2.3-beta.1+some.metadata": {1, 2, 3, "beta.1", "some.metadata", Complete},
-	"0.0.0":                                              {0, 0, 0, "", "", MajorMinorPatch},
-	"0.0.0-beta":                                         {0, 0, 0, "beta", "", MMPPre},
-	"0.0.100-beta.1":                                     {0, 0, 100, "beta.1", "", MMPPre},
-	"0.100.100-beta.1+some.metadata":                     {0, 100, 100, "beta.1", "some.metadata", Complete},
-	"100.100.100-beta.1+some.metadata":                   {100, 100, 100, "beta.1", "some.metadata", Complete},
-	"100.100.100-beta-dash-21+some.metadata":             {100, 100, 100, "beta-dash-21", "some.metadata", Complete},
-	"100.100.100-beta-dash-21+some-dashing--metadata.45": {100, 100, 100, "beta-dash-21", "some-dashing--metadata.45", Complete},
+	"1":                          testVersion(1, 0, 0, "", "", Major),
+	"1.2":                        testVersion(1, 2, 0, "", "", MajorMinor),
+	"1.2.3":                      testVersion(1, 2, 3, "", "", MajorMinorPatch),
+	"1.2.3-beta.1":               testVersion(1, 2, 3, "beta.1", "", MMPPre),
+	"1.2.3-beta.1+some.metadata": testVersion(1, 2, 3, "beta.1", "some.metadata", Complete),
+	"0.0.0":                                              testVersion(0, 0, 0, "", "", MajorMinorPatch),
+	"0.0.0-beta":                                         testVersion(0, 0, 0, "beta", "", MMPPre),
+	"0.0.100-beta.1":                                     testVersion(0, 0, 100, "beta.1", "", MMPPre),
+	"0.100.100-beta.1+some.metadata":                     testVersion(0, 100, 100, "beta.1", "some.metadata", Complete),
+	"100.100.100-beta.1+some.metadata":                   testVersion(100, 100, 100, "beta.1", "some.metadata", Complete),
+	"100.100.100-beta-dash-21+some.metadata":             testVersion(100, 100, 100, "beta-dash-21", "some.metadata", Complete),
+	"100.100.100-beta-dash-21+some-dashing--metadata.45": testVersion(100, 100, 100, "beta-dash-21", "some-dashing--metadata.45", Complete),
 }
 
 func TestString(t *testing.T) {
@@ -51,15 +59,15 @@ func TestParse(t *testing.T) {
 // String() is called on the resulting version, the original
 // input string is returned.
 var parseExactVersions = map[string]Version{
-	"1.2.3":                      {1, 2, 3, "", "", MajorMinorPatch},
-	"1.2.3-beta.1":               {1, 2, 3, "beta.1", "", MMPPre},
-	"1.2.3-beta.1+some.metadata": {1, 2, 3, "beta.1", "some.metadata", Complete},
-	"0.0.0":                                              {0, 0, 0, "", "", MajorMinorPatch},
-	"0.0.100-beta.1":                                     {0, 0, 100, "beta.1", "", MMPPre},
-	"0.100.100-beta.1+some.metadata":                     {0, 100, 100, "beta.1", "some.metadata", Complete},
-	"100.100.100-beta.1+some.metadata":                   {100, 100, 100, "beta.1", "some.metadata", Complete},
-	"100.100.100-beta-dash-21+some.metadata":             {100, 100, 100, "beta-dash-21", "some.metadata", Complete},
-	"100.100.100-beta-dash-21+some-dashing--metadata.45": {100, 100, 100, "beta-dash-21", "some-dashing--metadata.45", Complete},
+	"1.2.3":                      testVersion(1, 2, 3, "", "", MajorMinorPatch),
+	"1.2.3-beta.1":               testVersion(1, 2, 3, "beta.1", "", MMPPre),
+	"1.2.3-beta.1+some.metadata": testVersion(1, 2, 3, "beta.1", "some.metadata", Complete),
+	"0.0.0":                                              testVersion(0, 0, 0, "", "", MajorMinorPatch),
+	"0.0.100-beta.1":                                     testVersion(0, 0, 100, "beta.1", "", MMPPre),
+	"0.100.100-beta.1+some.metadata":                     testVersion(0, 100, 100, "beta.1", "some.metadata", Complete),
+	"100.100.100-beta.1+some.metadata":                   testVersion(100, 100, 100, "beta.1", "some.metadata", Complete),
+	"100.100.100-beta-dash-21+some.metadata":             testVersion(100, 100, 100, "beta-dash-21", "some.metadata", Complete),
+	"100.100.100-beta-dash-21+some-dashing--metadata.45": testVersion(100, 100, 100, "beta-dash-21", "some-dashing--metadata.45", Complete),
 }
 
 func TestParseExactSemver2_0_0(t *testing.T) {
@@ -286,6 +294,42 @@ func TestUnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestJSON_LosslessRoundTrip(t *testing.T) {
+	for input := range losslessVersions {
+		v := MustParseLossless(input)
+		j, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actual Version
+		if err := json.Unmarshal(j, &actual); err != nil {
+			t.Errorf("unmarshalling %s, marshalled from %q: %s", j, input, err)
+			continue
+		}
+		if !actual.ExactEquals(MustParse(v.Format(v.DefaultFormat))) || !actual.Equals(v) {
+			t.Errorf("got %q after JSON round trip of %q; want %q", actual, input, v.Format(v.DefaultFormat))
+		}
+	}
+}
+
+func TestYAML_LosslessRoundTrip(t *testing.T) {
+	for input := range losslessVersions {
+		v := MustParseLossless(input)
+		obj, err := v.MarshalYAML()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actual Version
+		err = actual.UnmarshalYAML(func(out interface{}) error {
+			*out.(*string) = obj.(string)
+			return nil
+		})
+		if err != nil || !actual.Equals(v) {
+			t.Errorf("got %q, %v after YAML round trip of %q", actual, err, input)
+		}
+	}
+}
+
 func (v Version) dump() string {
 	b, err := json.Marshal(v)
 	if err != nil {
@@ -293,3 +337,219 @@ func (v Version) dump() string {
 	}
 	return string(b)
 }
+
+var losslessVersions = map[string]Version{
+	"v01.2":            testVersion(1, 2, 0, "", "", MajorMinor),
+	"V1.02.003-beta":   testVersion(1, 2, 3, "beta", "", MMPPre),
+	"1.0.0+build.5":    testVersion(1, 0, 0, "", "build.5", Complete),
+	"v0.0.1-rc.1+meta": testVersion(0, 0, 1, "rc.1", "meta", Complete),
+}
+
+func TestParseLossless(t *testing.T) {
+	for input, expected := range losslessVersions {
+		actual, err := ParseLossless(input)
+		if err != nil {
+			t.Errorf("parsing %q: %s", input, err)
+			continue
+		}
+		if s := actual.String(); s != input {
+			t.Errorf("got String() == %q; want %q", s, input)
+		}
+		if o := actual.Original(); o != input {
+			t.Errorf("got Original() == %q; want %q", o, input)
+		}
+		if !actual.Equals(expected) {
+			t.Errorf("got %s; want a version equal to %s", actual.dump(), expected.dump())
+		}
+		if actual == expected {
+			t.Errorf("got %q == %q; want lossless versions to differ from constructed ones", input, expected)
+		}
+	}
+}
+
+func TestParseLossless_Errors(t *testing.T) {
+	for input, expected := range map[string]string{
+		"vx.1.2": "unexpected character 'x' at position 1",
+		"v1.x.2": "unexpected character 'x' at position 3",
+		"1.2.x":  "unexpected character 'x' at position 4",
+		"vv1":    "unexpected character 'v' at position 1",
+	} {
+		_, err := ParseLossless(input)
+		if err == nil {
+			t.Errorf("successfully parsed invalid string %q as version", input)
+			continue
+		}
+		if err.Error() != expected {
+			t.Errorf("got error message %q; expected %q", err.Error(), expected)
+		}
+	}
+}
+
+func TestOriginal_Modified(t *testing.T) {
+	v, err := ParseLossless("v01.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []Version{v.IncrementMinor(), v.SetPre("beta"), v.SetMeta("meta")} {
+		if o := m.Original(); o != "" {
+			t.Errorf("got Original() == %q after modification; want empty", o)
+		}
+	}
+	if s := v.IncrementMinor().String(); s != "1.3.0" {
+		t.Errorf("got %q after IncrementMinor; want %q", s, "1.3.0")
+	}
+	v.Patch = 4
+	if s := v.String(); s != "1.2.4" {
+		t.Errorf("got %q after setting Patch; want %q", s, "1.2.4")
+	}
+	if o := MustParse("1.2.3").Original(); o != "" {
+		t.Errorf("got Original() == %q for version parsed with Parse; want empty", o)
+	}
+}
+
+func TestExactEquals(t *testing.T) {
+	tests := []struct {
+		a, b     Version
+		expected bool
+	}{
+		{MustParse("1.0.0"), MustParse("1.0.0"), true},
+		{MustParse("1.0.0"), NewVersion(1, 0, 0, "", ""), true},
+		{MustParse("1.0.0+a"), MustParse("1.0.0+b"), false},
+		{MustParse("1.0"), MustParse("1.0.0"), false},
+		{MustParseLossless("v1.0.0"), MustParse("1.0.0"), false},
+		{MustParseLossless("v1.0.0"), MustParseLossless("v1.0.0"), true},
+		{MustParseLossless("1.0.0"), MustParse("1.0.0"), true},
+	}
+	for _, test := range tests {
+		if actual := test.a.ExactEquals(test.b); actual != test.expected {
+			t.Errorf("got %q.ExactEquals(%q) == %t; want %t", test.a, test.b, actual, test.expected)
+		}
+		if !test.a.Equals(test.b) && test.a.Meta == test.b.Meta {
+			t.Errorf("%q and %q should be semantically equal", test.a, test.b)
+		}
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	for input, expected := range reversibleParseVersions {
+		actual, err := ParseBytes([]byte(input))
+		if err != nil {
+			t.Error(err)
+		}
+		if actual != expected {
+			t.Errorf("Got ParseBytes(%q) == % +v; expected % +v", input, actual, expected)
+		}
+	}
+	for input, expectedError := range invalidVersions {
+		_, err := ParseBytes([]byte(input))
+		if err == nil || !strings.Contains(err.Error(), expectedError) {
+			t.Errorf("got error %v parsing %q; expected %q", err, input, expectedError)
+		}
+	}
+}
+
+func TestParseErrors_NonASCII(t *testing.T) {
+	_, err := Parse("1.2é")
+	if expected := (UnexpectedCharacter{'é', 3}); err != expected {
+		t.Errorf("got error %v; want %v", err, expected)
+	}
+}
+
+func TestAppendFormat(t *testing.T) {
+	v := MustParse("1.2.3-beta.1+abc")
+	for _, format := range []string{"", Complete, MajorMinor, "M.m.p" + PreRaw, "%M.%m{.%p}{-%r}", "%M}"} {
+		expected := "prefix:" + v.Format(format)
+		if actual := string(v.AppendFormat([]byte("prefix:"), format)); actual != expected {
+			t.Errorf("got AppendFormat(%q) == %q; want %q", format, actual, expected)
+		}
+	}
+}
+
+// TestAllocs guards against allocations creeping into the hot paths of
+// parsing, comparing and formatting versions.
+func TestAllocs(t *testing.T) {
+	input := []byte("1.22.333")
+	a, b := MustParse("1.2.3-beta.11.x"), MustParse("1.2.3-beta.2.x")
+	buf := make([]byte, 0, 64)
+	tests := map[string]func(){
+		"Parse":             func() { _, _ = Parse("1.22.333-beta.1+linux") },
+		"ParseExactSemver2": func() { _, _ = ParseExactSemver2("1.22.333-rc.1") },
+		"ParseBytes":        func() { _, _ = ParseBytes(input) },
+		"Less":              func() { _ = a.Less(b) },
+		"Equals":            func() { _ = a.Equals(b) },
+		"AppendFormat":      func() { _ = a.AppendFormat(buf, Complete) },
+		"AppendFormat (format language)": func() {
+			_ = a.AppendFormat(buf, "v%M.%m.%03p{-%[1]r}")
+		},
+	}
+	for name, f := range tests {
+		if allocs := testing.AllocsPerRun(100, f); allocs != 0 {
+			t.Errorf("%s: got %v allocations; want 0", name, allocs)
+		}
+	}
+}
+
+var benchmarkVersionStrings = []string{
+	"1.2.3", "0.0.1", "10.20.30", "1.2.3-beta.1", "1.2.3-rc.1+build.5", "2.0", "3",
+}
+
+func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = Parse(benchmarkVersionStrings[i%len(benchmarkVersionStrings)])
+	}
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	inputs := make([][]byte, len(benchmarkVersionStrings))
+	for i, s := range benchmarkVersionStrings {
+		inputs[i] = []byte(s)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseBytes(inputs[i%len(inputs)])
+	}
+}
+
+func BenchmarkLess(b *testing.B) {
+	vl := MustParseList(benchmarkVersionStrings...)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = vl[i%len(vl)].Less(vl[(i+1)%len(vl)])
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	vl := MustParseList(benchmarkVersionStrings...)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = vl[i%len(vl)].String()
+	}
+}
+
+func BenchmarkAppendFormat(b *testing.B) {
+	vl := MustParseList(benchmarkVersionStrings...)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = vl[i%len(vl)].AppendFormat(buf[:0], Complete)
+	}
+}
+
+func BenchmarkVersionList_Sort(b *testing.B) {
+	vl := newLargeVersionList()
+	for i := range vl {
+		if i%3 == 0 {
+			vl[i] = vl[i].SetPre(fmt.Sprintf("rc.%d", i%7))
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vl.Sorted()
+	}
+}